@@ -9,24 +9,23 @@ import (
 	"crypto/rsa"
 	"crypto/sha512"
 	"crypto/x509"
-	"encoding/binary"
 	"encoding/pem"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 )
 
 func (sc *siodbConn) authenticate() (err error) {
 
-	var buf [binary.MaxVarintLen32]byte
-	var encodedLength int
-
 	// Begin Session Request
 	beginSessionRequest := &BeginSessionRequest{
-		UserName: sc.cfg.user,
+		UserName: sc.cfg.User,
 	}
 	sc.debug("authenticate | %v", beginSessionRequest)
-	encodedLength = binary.PutUvarint(buf[:], uint64(5))
-	sc.netConn.Write(buf[:encodedLength])
-	writeMessage(sc.netConn, beginSessionRequest)
+	if err = sc.writeMessage(5, beginSessionRequest); err != nil {
+		return &DriverError{Message: "Unable to send the session request | " + err.Error(), Err: err}
+	}
 
 	// Get Session Response
 	var beginSessionResponse BeginSessionResponse
@@ -36,14 +35,14 @@ func (sc *siodbConn) authenticate() (err error) {
 
 	// Check if Siodb has started the session
 	if !beginSessionResponse.GetSessionStarted() {
-		return &siodbDriverError{"Starting session failed: " + beginSessionResponse.GetMessage().GetText()}
+		return &DriverError{Message: "Starting session failed: " + beginSessionResponse.GetMessage().GetText(), Err: ErrAuthenticationFailed}
 	}
-	sc.debug("authenticate | beginSessionResponse | %v", sc.cfg.privateKey)
+	sc.debug("authenticate | beginSessionResponse | %v", sc.cfg.PrivateKey)
 
 	// Sign challenge sha512 digest
 	sha512 := sha512.New()
 	sha512.Write(beginSessionResponse.GetChallenge())
-	signature, err := rsa.SignPKCS1v15(nil, sc.cfg.privateKey, crypto.SHA512, sha512.Sum(nil))
+	signature, err := rsa.SignPKCS1v15(nil, sc.cfg.PrivateKey, crypto.SHA512, sha512.Sum(nil))
 	sc.debug("authenticate | signature | %v", err)
 
 	// Begin Session Request
@@ -51,9 +50,9 @@ func (sc *siodbConn) authenticate() (err error) {
 		Signature: signature,
 	}
 	sc.debug("authenticate | clientAuthenticationRequest | %v", clientAuthenticationRequest)
-	encodedLength = binary.PutUvarint(buf[:], uint64(7))
-	sc.netConn.Write(buf[:encodedLength])
-	writeMessage(sc.netConn, clientAuthenticationRequest)
+	if err = sc.writeMessage(7, clientAuthenticationRequest); err != nil {
+		return &DriverError{Message: "Unable to send the authentication request | " + err.Error(), Err: err}
+	}
 
 	// Get Session Response
 	var clientAuthenticationResponse ClientAuthenticationResponse
@@ -64,7 +63,7 @@ func (sc *siodbConn) authenticate() (err error) {
 
 	// Check if Siodb has authenticated the session
 	if !clientAuthenticationResponse.GetAuthenticated() {
-		return &siodbDriverError{"Authentication failed: " + clientAuthenticationResponse.GetMessage().GetText()}
+		return &DriverError{Message: "Authentication failed: " + clientAuthenticationResponse.GetMessage().GetText(), Err: ErrAuthenticationFailed}
 	}
 	sc.debug("authenticate | %v", clientAuthenticationResponse)
 
@@ -76,15 +75,22 @@ func (sc *siodbConn) authenticate() (err error) {
 
 func loadPrivateKey(rsaPKeyPath string, rsaPKeyPwd string) (pk *rsa.PrivateKey, err error) {
 
+	if rsaPKeyPath, err = expandHome(rsaPKeyPath); err != nil {
+		return pk, &DriverError{Message: "Unable to find the home directory of the identity file | " + err.Error(), Err: err}
+	}
+
 	priv, err := ioutil.ReadFile(rsaPKeyPath)
 	if err != nil {
-		return pk, &siodbDriverError{"Paring URI: Indentity file '" + rsaPKeyPath + "' not found."}
+		return pk, &DriverError{Message: "Unable to read the identity file '" + rsaPKeyPath + "' | " + err.Error(), Err: err}
 	}
 
 	privatePem, _ := pem.Decode(priv)
 	var privatePemBytes []byte
+	if privatePem == nil {
+		return pk, &DriverError{Message: "Identity file '" + rsaPKeyPath + "' is not PEM encoded."}
+	}
 	if privatePem.Type != "RSA PRIVATE KEY" {
-		return pk, &siodbDriverError{"RSA private key is of the wrong type."}
+		return pk, &DriverError{Message: "RSA private key is of the wrong type."}
 	}
 
 	if rsaPKeyPwd != "" {
@@ -96,15 +102,30 @@ func loadPrivateKey(rsaPKeyPath string, rsaPKeyPwd string) (pk *rsa.PrivateKey,
 	var parsedPrivateKey interface{}
 	if parsedPrivateKey, err = x509.ParsePKCS1PrivateKey(privatePemBytes); err != nil {
 		if parsedPrivateKey, err = x509.ParsePKCS8PrivateKey(privatePemBytes); err != nil {
-			return pk, &siodbDriverError{"Unable to parse RSA private key."}
+			return pk, &DriverError{Message: "Unable to parse RSA private key."}
 		}
 	}
 
 	var ok bool
 	pk, ok = parsedPrivateKey.(*rsa.PrivateKey)
 	if !ok {
-		return pk, &siodbDriverError{"Unable to parse RSA private key"}
+		return pk, &DriverError{Message: "Unable to parse RSA private key"}
 	}
 
 	return pk, nil
 }
+
+// expandHome replaces a leading "~" in path with the home directory of the user.
+func expandHome(path string) (string, error) {
+
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path, err
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
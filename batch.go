@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// BatchExecer is implemented by the driver connections. It is reachable
+// through sql.Conn.Raw:
+//
+//	err = conn.Raw(func(driverConn interface{}) error {
+//		results, err = driverConn.(siodb.BatchExecer).ExecBatch(ctx, script, siodb.BatchOptions{})
+//		return err
+//	})
+type BatchExecer interface {
+	ExecBatch(ctx context.Context, query string, opts BatchOptions) ([]BatchResult, error)
+}
+
+// BatchOptions controls the execution of a batch.
+type BatchOptions struct {
+	// ContinueOnError sends the whole script as a single command: the server
+	// runs every statement, even after a failed one, and the results of all
+	// statements are returned. By default the statements are sent one at a
+	// time and ExecBatch stops at the first failed statement, the following
+	// ones not being sent.
+	ContinueOnError bool
+}
+
+// BatchResult is the result of one statement of a batch.
+type BatchResult struct {
+	HasAffectedRowCount bool
+	AffectedRowCount    int64
+	StatusMessages      []*StatusMessage // Messages with a status code
+	Messages            []string         // Free text messages
+	Err                 error            // Server error of the statement, if any
+}
+
+// ExecBatch runs the statements of query and returns one result per
+// statement, see BatchOptions. Datasets returned by the statements are dropped.
+func (sc *siodbConn) ExecBatch(ctx context.Context, query string, opts BatchOptions) ([]BatchResult, error) {
+
+	var results []BatchResult
+	var err error
+
+	if sc.bad {
+		return nil, driver.ErrBadConn
+	}
+
+	if err = sc.checkReadOnly(query); err != nil {
+		return nil, err
+	}
+
+	watcher, err := sc.startWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.stop()
+
+	if opts.ContinueOnError {
+		results, err = sc.execBatchCommand(results, query)
+		return results, watcher.check(err)
+	}
+
+	for _, statement := range splitStatements(query) {
+		if firstKeyword(statement) == "" {
+			continue
+		}
+		if results, err = sc.execBatchCommand(results, statement); err != nil {
+			return results, watcher.check(err)
+		}
+		if result := results[len(results)-1]; result.Err != nil {
+			return results, result.Err
+		}
+	}
+
+	return results, nil
+}
+
+// execBatchCommand sends the statements as one command and appends the
+// result of each statement to results.
+func (sc *siodbConn) execBatchCommand(results []BatchResult, statements string) ([]BatchResult, error) {
+
+	var sr ServerResponse
+	var err error
+
+	if err = sc.writeServerCommand(statements); err != nil {
+		return results, err
+	}
+
+	for {
+		if sr, err = sc.readServer(); err != nil {
+			return results, &DriverError{Message: "Fail to read server response: " + err.Error()}
+		}
+
+		if !sc.completed {
+			if _, err = sc.cleanupBuffer(); err != nil {
+				return results, err
+			}
+		}
+
+		result := BatchResult{
+			HasAffectedRowCount: sr.HasAffectedRowCount,
+			AffectedRowCount:    int64(sr.AffectedRowCount),
+			StatusMessages:      sr.Message,
+			Messages:            sr.FreetextMessage,
+			Err:                 checkServerError(sr.Message),
+		}
+		results = append(results, result)
+		sc.debug("ExecBatch | Statement %d: %v.", len(results), result.Err)
+
+		if !sc.moreResponses() {
+			return results, nil
+		}
+	}
+}
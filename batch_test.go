@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func batchStream(t *testing.T) []byte {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:           1,
+		ResponseCount:       3,
+		HasAffectedRowCount: true,
+		AffectedRowCount:    2,
+	})
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:  1,
+		ResponseId: 1,
+		Message:    []*StatusMessage{{StatusCode: 7, Text: "Failed"}},
+	})
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:  1,
+		ResponseId: 2,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "A", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+		},
+		FreetextMessage: []string{"Done"},
+	})
+	stream = append(stream, 1, 42, 0)
+
+	return stream
+}
+
+// batchConn serves the responses from a stream and records the commands.
+type batchConn struct {
+	recordConn
+	responses *bytes.Reader
+}
+
+func (bc *batchConn) Read(p []byte) (int, error) {
+	return bc.responses.Read(p)
+}
+
+// commands returns the text of the commands written to the connection.
+func (bc *batchConn) commands() []string {
+
+	var texts []string
+	sc := newStreamConn(bc.buf.Bytes())
+	for {
+		var command Command
+		if _, err := sc.ReadMessage(1, &command); err != nil {
+			return texts
+		}
+		texts = append(texts, command.Text)
+	}
+}
+
+func TestExecBatchStopOnError(t *testing.T) {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:           1,
+		HasAffectedRowCount: true,
+		AffectedRowCount:    2,
+	})
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID: 1,
+		Message:   []*StatusMessage{{StatusCode: 7, Text: "Failed"}},
+	})
+
+	bc := &batchConn{responses: bytes.NewReader(stream)}
+	sc := newConn(bc, Config{})
+	sc.completed = true
+	results, err := sc.ExecBatch(context.Background(), "INSERT INTO t VALUES (';');\n-- next\nINSERT ...;\nDELETE FROM t;\n", BatchOptions{})
+	if err == nil {
+		t.Fatalf("ExecBatch: error expected")
+	}
+	if len(results) != 2 || results[0].AffectedRowCount != 2 || results[1].Err == nil {
+		t.Fatalf("ExecBatch: unexpected results %+v", results)
+	}
+	if sc.moreResponses() || !sc.completed || !sc.IsValid() {
+		t.Fatalf("ExecBatch: responses left on the connection")
+	}
+
+	// The statement after the failed one is not sent.
+	commands := bc.commands()
+	expected := []string{"INSERT INTO t VALUES (';')", "\n-- next\nINSERT ..."}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Fatalf("ExecBatch: commands sent %q != %q", commands, expected)
+	}
+}
+
+func TestExecBatchContinueOnError(t *testing.T) {
+
+	sc := newFakeServerConn(t, batchStream(t))
+	results, err := sc.ExecBatch(context.Background(), "INSERT ...; INSERT ...; SELECT ...", BatchOptions{ContinueOnError: true})
+	if err != nil {
+		t.Fatalf("ExecBatch: %v", err)
+	}
+	if len(results) != 3 || results[1].Err == nil || results[2].Err != nil || results[2].Messages[0] != "Done" {
+		t.Fatalf("ExecBatch: unexpected results %+v", results)
+	}
+	if !sc.completed || !sc.IsValid() {
+		t.Fatalf("ExecBatch: rows left on the connection")
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// placeholder is a parameter marker found in a statement: '?' and '$N' are
+// positional (ordinal), ':name' is named.
+type placeholder struct {
+	ordinal int
+	name    string
+}
+
+// parsedQuery is a statement split around its placeholders:
+// len(parts) == len(placeholders)+1.
+type parsedQuery struct {
+	query        string
+	parts        []string
+	placeholders []placeholder
+	numInput     int
+}
+
+// parseQuery splits the query around its placeholders. Quoted strings,
+// quoted identifiers and comments are left untouched.
+func parseQuery(query string) *parsedQuery {
+
+	pq := &parsedQuery{query: query}
+	var part strings.Builder
+	var positional int
+	named := false
+
+	for i := 0; i < len(query); i++ {
+		if end := skipQuoted(query, i); end >= 0 {
+			part.WriteString(query[i : end+1])
+			i = end
+			continue
+		}
+
+		c := query[i]
+		switch {
+
+		case c == '?':
+			positional++
+			pq.addPlaceholder(&part, placeholder{ordinal: positional})
+
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			end := i + 1
+			for end < len(query) && isDigit(query[end]) {
+				end++
+			}
+			ordinal, _ := strconv.Atoi(query[i+1 : end])
+			pq.addPlaceholder(&part, placeholder{ordinal: ordinal})
+			i = end - 1
+
+		case c == ':' && i+1 < len(query) && isIdentStart(query[i+1]) && (i == 0 || query[i-1] != ':'):
+			end := i + 1
+			for end < len(query) && (isIdentStart(query[end]) || isDigit(query[end])) {
+				end++
+			}
+			pq.addPlaceholder(&part, placeholder{name: query[i+1 : end]})
+			named = true
+			i = end - 1
+
+		default:
+			part.WriteByte(c)
+		}
+	}
+	pq.parts = append(pq.parts, part.String())
+
+	if named {
+		pq.numInput = -1
+	}
+
+	return pq
+}
+
+// skipQuoted returns the index of the last byte of the quoted string, quoted
+// identifier or comment starting at query[i], or -1 if none starts there.
+func skipQuoted(query string, i int) int {
+
+	c := query[i]
+	switch {
+
+	case c == '\'' || c == '"':
+		// Quoted string or identifier, a doubled quote is an escaped quote.
+		end := i + 1
+		for end < len(query) {
+			if query[end] == c {
+				if end+1 < len(query) && query[end+1] == c {
+					end += 2
+					continue
+				}
+				break
+			}
+			end++
+		}
+		if end >= len(query) {
+			end = len(query) - 1
+		}
+		return end
+
+	case c == '-' && i+1 < len(query) && query[i+1] == '-':
+		end := strings.IndexByte(query[i:], '\n')
+		if end < 0 {
+			return len(query) - 1
+		}
+		return i + end
+
+	case c == '/' && i+1 < len(query) && query[i+1] == '*':
+		end := strings.Index(query[i+2:], "*/")
+		if end < 0 {
+			return len(query) - 1
+		}
+		return i + end + 3
+
+	default:
+		return -1
+	}
+}
+
+// splitStatements splits the query at the semicolons found outside of quoted
+// strings, quoted identifiers and comments.
+func splitStatements(query string) []string {
+
+	var statements []string
+	start := 0
+
+	for i := 0; i < len(query); i++ {
+		if end := skipQuoted(query, i); end >= 0 {
+			i = end
+			continue
+		}
+		if query[i] == ';' {
+			statements = append(statements, query[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(statements, query[start:])
+}
+
+func (pq *parsedQuery) addPlaceholder(part *strings.Builder, p placeholder) {
+
+	pq.parts = append(pq.parts, part.String())
+	part.Reset()
+	pq.placeholders = append(pq.placeholders, p)
+	if p.ordinal > pq.numInput {
+		pq.numInput = p.ordinal
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// bindCommand interpolates the arguments into the statement as Siodb SQL
+// literals. time.Time values are converted to loc before they are formatted.
+// The io.Reader arguments are kept to be read while the command is sent.
+func (pq *parsedQuery) bindCommand(args []driver.NamedValue, loc *time.Location) (*commandText, error) {
+
+	if len(args) == 0 {
+		return &commandText{chunks: []string{pq.query}}, nil
+	}
+	if len(pq.placeholders) == 0 {
+		return nil, &DriverError{Message: fmt.Sprintf("Statement has no placeholder but %d argument(s) provided.", len(args))}
+	}
+	if pq.numInput >= 0 && len(args) != pq.numInput {
+		return nil, &DriverError{Message: fmt.Sprintf("Statement expects %d argument(s), %d provided.", pq.numInput, len(args))}
+	}
+
+	ct := &commandText{}
+	var text strings.Builder
+	for idx, p := range pq.placeholders {
+		text.WriteString(pq.parts[idx])
+
+		arg, err := pq.findArg(p, args)
+		if err != nil {
+			return nil, err
+		}
+
+		if r, ok := readerValue(arg.Value); ok {
+			ct.chunks = append(ct.chunks, text.String())
+			ct.readers = append(ct.readers, r)
+			text.Reset()
+			continue
+		}
+
+		literal, err := formatValue(arg.Value, loc)
+		if err != nil {
+			return nil, err
+		}
+		text.WriteString(literal)
+	}
+	text.WriteString(pq.parts[len(pq.parts)-1])
+	ct.chunks = append(ct.chunks, text.String())
+
+	return ct, nil
+}
+
+// readerValue returns the reader of a binary parameter read while the
+// command is sent. A driver.Valuer is formatted from its value instead.
+func readerValue(value interface{}) (io.Reader, bool) {
+
+	if _, ok := value.(driver.Valuer); ok {
+		return nil, false
+	}
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, false
+	}
+	r, ok := value.(io.Reader)
+
+	return r, ok
+}
+
+func (pq *parsedQuery) findArg(p placeholder, args []driver.NamedValue) (driver.NamedValue, error) {
+
+	for _, arg := range args {
+		if p.name != "" && arg.Name == p.name {
+			return arg, nil
+		}
+		if p.name == "" && arg.Name == "" && arg.Ordinal == p.ordinal {
+			return arg, nil
+		}
+	}
+
+	if p.name != "" {
+		return driver.NamedValue{}, &DriverError{Message: "No argument provided for parameter ':" + p.name + "'."}
+	}
+	return driver.NamedValue{}, &DriverError{Message: "No argument provided for parameter " + strconv.Itoa(p.ordinal) + "."}
+}
+
+// formatValue returns the Siodb SQL literal of a value.
+func formatValue(value interface{}, loc *time.Location) (string, error) {
+
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	case string:
+		return quoteString(v)
+	case json.RawMessage:
+		if v == nil {
+			return "NULL", nil
+		}
+		return quoteString(string(v))
+	case []byte:
+		if v == nil {
+			return "NULL", nil
+		}
+		return "x'" + hex.EncodeToString(v) + "'", nil
+	case time.Time:
+		return "'" + v.In(loc).Format("2006-01-02 15:04:05.999999999") + "'", nil
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "NULL", nil
+		}
+		dv, err := v.Value()
+		if err != nil {
+			return "", err
+		}
+		if _, ok := dv.(driver.Valuer); ok {
+			return "", &DriverError{Message: fmt.Sprintf("Value of type %T returned another driver.Valuer.", value)}
+		}
+		return formatValue(dv, loc)
+	default:
+		return "", &DriverError{Message: fmt.Sprintf("Unsupported parameter type %T.", value), Err: ErrUnsupportedType}
+	}
+}
+
+func formatFloat(f float64, bitSize int) (string, error) {
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", &DriverError{Message: "NaN and infinite values can't be bound."}
+	}
+
+	return strconv.FormatFloat(f, 'g', -1, bitSize), nil
+}
+
+func quoteString(s string) (string, error) {
+
+	if strings.IndexByte(s, 0) >= 0 {
+		return "", &DriverError{Message: "String parameters can't contain NUL characters."}
+	}
+
+	return "'" + strings.Replace(s, "'", "''", -1) + "'", nil
+}
+
+// CheckNamedValue accepts the integer types as they are so that unsigned
+// values above math.MaxInt64 are bound without any conversion, and keeps
+// json.RawMessage apart from []byte so that it is bound as a JSON document.
+// See https://golang.org/pkg/database/sql/driver/#NamedValueChecker
+func (sc *siodbConn) CheckNamedValue(nv *driver.NamedValue) error {
+
+	switch nv.Value.(type) {
+	case int, int8, int16, int32, uint, uint8, uint16, uint32, uint64, float32, json.RawMessage:
+		return nil
+	case driver.Valuer:
+		// Resolved when binding, so that values such as NullUint64 above
+		// math.MaxInt64 don't go through the default conversion.
+		return nil
+	case io.Reader:
+		// Read while the command is sent.
+		return nil
+	default:
+		return driver.ErrSkip
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"math"
+	"testing"
+	"time"
+)
+
+// bindText binds the arguments into the query and returns the command text,
+// for arguments without reader.
+func bindText(query string, args []driver.NamedValue, loc *time.Location) (string, error) {
+
+	ct, err := parseQuery(query).bindCommand(args, loc)
+	if err != nil {
+		return "", err
+	}
+	if len(ct.readers) > 0 {
+		return "", &DriverError{Message: "Reader parameters can't be bound in the statement text.", Err: ErrUnsupportedType}
+	}
+
+	return ct.chunks[0], nil
+}
+
+func TestBind(t *testing.T) {
+
+	ts := time.Date(2020, time.March, 4, 5, 6, 7, 800, time.UTC)
+
+	tests := []struct {
+		query    string
+		args     []driver.NamedValue
+		expected string
+	}{
+		{"SELECT * FROM t", nil, "SELECT * FROM t"},
+		{"SELECT * FROM t WHERE a = ? AND b = ?",
+			[]driver.NamedValue{{Ordinal: 1, Value: int64(-1)}, {Ordinal: 2, Value: "it's"}},
+			"SELECT * FROM t WHERE a = -1 AND b = 'it''s'"},
+		{"INSERT INTO t VALUES ($2, $1)",
+			[]driver.NamedValue{{Ordinal: 1, Value: true}, {Ordinal: 2, Value: nil}},
+			"INSERT INTO t VALUES (NULL, true)"},
+		{"INSERT INTO t VALUES (:a, :b, :a)",
+			[]driver.NamedValue{{Name: "a", Ordinal: 1, Value: []byte{0xca, 0xfe}}, {Name: "b", Ordinal: 2, Value: ts}},
+			"INSERT INTO t VALUES (x'cafe', '2020-03-04 05:06:07.0000008', x'cafe')"},
+		{"SELECT '?', \"?\" -- ?\n, ? /* ? */ FROM t",
+			[]driver.NamedValue{{Ordinal: 1, Value: uint64(math.MaxUint64)}},
+			"SELECT '?', \"?\" -- ?\n, 18446744073709551615 /* ? */ FROM t"},
+		{"SELECT ? FROM t",
+			[]driver.NamedValue{{Ordinal: 1, Value: float32(222.222)}},
+			"SELECT 222.222 FROM t"},
+	}
+
+	for _, test := range tests {
+		text, err := bindText(test.query, test.args, time.UTC)
+		if err != nil {
+			t.Fatalf("bind(%q): unexpected error %s", test.query, err.Error())
+		}
+		if text != test.expected {
+			t.Fatalf("bind(%q): %q != %q", test.query, text, test.expected)
+		}
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+
+	tests := []struct {
+		query string
+		args  []driver.NamedValue
+	}{
+		{"SELECT * FROM t", []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}},
+		{"SELECT ?, ?", []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}},
+		{"SELECT :a", []driver.NamedValue{{Name: "b", Ordinal: 1, Value: int64(1)}}},
+		{"SELECT ?", []driver.NamedValue{{Ordinal: 1, Value: math.NaN()}}},
+		{"SELECT ?", []driver.NamedValue{{Ordinal: 1, Value: "a\x00b"}}},
+		{"SELECT ?", []driver.NamedValue{{Ordinal: 1, Value: struct{}{}}}},
+	}
+
+	for _, test := range tests {
+		if _, err := bindText(test.query, test.args, time.UTC); err == nil {
+			t.Fatalf("bind(%q, %v): error expected", test.query, test.args)
+		}
+	}
+}
+
+func TestParseQueryNumInput(t *testing.T) {
+
+	tests := []struct {
+		query    string
+		numInput int
+	}{
+		{"SELECT * FROM t", 0},
+		{"SELECT * FROM t WHERE a = ? AND b = ?", 2},
+		{"SELECT * FROM t WHERE a = $3 AND b = $1", 3},
+		{"SELECT * FROM t WHERE a = :a", -1},
+		{"SELECT '?', ':a' FROM t WHERE a = ? -- $4", 1},
+	}
+
+	for _, test := range tests {
+		if numInput := parseQuery(test.query).numInput; numInput != test.numInput {
+			t.Fatalf("parseQuery(%q).numInput: %d != %d", test.query, numInput, test.numInput)
+		}
+	}
+}
+
+func TestBindTimeLocation(t *testing.T) {
+
+	ts := time.Date(2020, time.June, 1, 12, 30, 0, 0, time.UTC)
+	args := []driver.NamedValue{{Ordinal: 1, Value: ts}}
+
+	text, err := bindText("SELECT ?", args, time.FixedZone("", 2*3600))
+	if err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if expected := "SELECT '2020-06-01 14:30:00'"; text != expected {
+		t.Fatalf("bind: %q != %q", text, expected)
+	}
+}
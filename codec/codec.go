@@ -0,0 +1,403 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+// Package codec encodes and decodes the binary formats of the values sent by
+// Siodb in the rows of a result set. It needs no connection, so it can be used
+// to produce and parse these formats offline.
+//
+// The Go form of the values of each data type is:
+//
+//	Bool                      bool
+//	Int8, Uint8 ... Uint64    int8, uint8 ... uint64
+//	Float, Double             float32, float64
+//	Text, NText, XML          string
+//	Binary, JSON              []byte
+//	Date, Time, Timestamp     time.Time
+//	UUID                      [16]byte
+//	Struct                    []interface{}, see EncodeStruct and DecodeStruct
+//
+// TimeWithTZ, TimestampWithTZ, DateInterval and TimeInterval are not
+// supported: the protocol files don't describe their formats.
+//
+// NText values are read and written as a varint length in bytes followed by
+// UTF-16LE code units. The protocol files don't describe this layout either:
+// it is assumed and not yet checked against server values.
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+// DataType is a column data type. The values are those of
+// siodb.ColumnDataType.
+type DataType int32
+
+// Data types.
+const (
+	Bool            DataType = 0
+	Int8            DataType = 1
+	Uint8           DataType = 2
+	Int16           DataType = 3
+	Uint16          DataType = 4
+	Int32           DataType = 5
+	Uint32          DataType = 6
+	Int64           DataType = 7
+	Uint64          DataType = 8
+	Float           DataType = 9
+	Double          DataType = 10
+	Text            DataType = 11
+	NText           DataType = 12
+	Binary          DataType = 13
+	Date            DataType = 14
+	Time            DataType = 15
+	TimeWithTZ      DataType = 16
+	Timestamp       DataType = 17
+	TimestampWithTZ DataType = 18
+	DateInterval    DataType = 19
+	TimeInterval    DataType = 20
+	Struct          DataType = 21
+	XML             DataType = 22
+	JSON            DataType = 23
+	UUID            DataType = 24
+)
+
+var dataTypeNames = [...]string{
+	"BOOL", "INT8", "UINT8", "INT16", "UINT16", "INT32", "UINT32", "INT64", "UINT64",
+	"FLOAT", "DOUBLE", "TEXT", "NTEXT", "BINARY", "DATE", "TIME", "TIME_WITH_TZ",
+	"TIMESTAMP", "TIMESTAMP_WITH_TZ", "DATE_INTERVAL", "TIME_INTERVAL", "STRUCT",
+	"XML", "JSON", "UUID",
+}
+
+func (t DataType) String() string {
+
+	if t >= 0 && int(t) < len(dataTypeNames) {
+		return dataTypeNames[t]
+	}
+
+	return fmt.Sprintf("DataType(%d)", int32(t))
+}
+
+var (
+	// ErrUnsupportedType is returned for data types the codec can't handle.
+	ErrUnsupportedType = errors.New("codec: unsupported data type")
+	// ErrInvalidData is returned when the bytes don't follow the format of the data type.
+	ErrInvalidData = errors.New("codec: invalid data")
+)
+
+// Encode writes a non-null value of a data type. STRUCT values are written
+// with EncodeStruct.
+func Encode(w io.Writer, t DataType, value interface{}) error {
+
+	var buff []byte
+
+	switch t {
+	case Bool:
+		v, ok := value.(bool)
+		if !ok {
+			return typeError(t, value)
+		}
+		if v {
+			buff = []byte{1}
+		} else {
+			buff = []byte{0}
+		}
+	case Int8:
+		v, ok := value.(int8)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = []byte{byte(v)}
+	case Uint8:
+		v, ok := value.(uint8)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = []byte{v}
+	case Int16:
+		v, ok := value.(int16)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendUint16(nil, uint16(v))
+	case Uint16:
+		v, ok := value.(uint16)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendUint16(nil, v)
+	case Int32:
+		v, ok := value.(int32)
+		if !ok {
+			return typeError(t, value)
+		}
+		// Negative values are sent as the 64-bit two's complement.
+		buff = appendUvarint(nil, uint64(int64(v)))
+	case Uint32:
+		v, ok := value.(uint32)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendUvarint(nil, uint64(v))
+	case Int64:
+		v, ok := value.(int64)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendUvarint(nil, uint64(v))
+	case Uint64:
+		v, ok := value.(uint64)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendUvarint(nil, v)
+	case Float:
+		v, ok := value.(float32)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendUint32(nil, math.Float32bits(v))
+	case Double:
+		v, ok := value.(float64)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendUint64(nil, math.Float64bits(v))
+	case Text, XML:
+		v, ok := value.(string)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = append(appendUvarint(nil, uint64(len(v))), v...)
+	case NText:
+		v, ok := value.(string)
+		if !ok {
+			return typeError(t, value)
+		}
+		// UTF-16LE code units, length in bytes: an assumed layout, see the package documentation.
+		units := utf16.Encode([]rune(v))
+		buff = appendUvarint(nil, uint64(len(units)*2))
+		for _, unit := range units {
+			buff = appendUint16(buff, unit)
+		}
+	case Binary, JSON:
+		v, ok := value.([]byte)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = append(appendUvarint(nil, uint64(len(v))), v...)
+	case Date, Time, Timestamp:
+		v, ok := value.(time.Time)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = appendTime(nil, t, v)
+	case UUID:
+		v, ok := value.([16]byte)
+		if !ok {
+			return typeError(t, value)
+		}
+		buff = v[:]
+	case Struct:
+		return fmt.Errorf("%w: %s values are encoded with EncodeStruct", ErrUnsupportedType, t)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+
+	_, err := w.Write(buff)
+	return err
+}
+
+// Decode reads a non-null value of a data type. Dates and times without time
+// zone are returned in loc, time.Local if nil. STRUCT values are read with
+// DecodeStruct.
+func Decode(r io.Reader, t DataType, loc *time.Location) (interface{}, error) {
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	switch t {
+	case Bool:
+		var buff [1]byte
+		if _, err := io.ReadFull(r, buff[:]); err != nil {
+			return nil, err
+		}
+		return buff[0] != 0, nil
+	case Int8:
+		var buff [1]byte
+		if _, err := io.ReadFull(r, buff[:]); err != nil {
+			return nil, err
+		}
+		return int8(buff[0]), nil
+	case Uint8:
+		var buff [1]byte
+		if _, err := io.ReadFull(r, buff[:]); err != nil {
+			return nil, err
+		}
+		return buff[0], nil
+	case Int16:
+		var buff [2]byte
+		if _, err := io.ReadFull(r, buff[:]); err != nil {
+			return nil, err
+		}
+		return int16(binary.LittleEndian.Uint16(buff[:])), nil
+	case Uint16:
+		var buff [2]byte
+		if _, err := io.ReadFull(r, buff[:]); err != nil {
+			return nil, err
+		}
+		return binary.LittleEndian.Uint16(buff[:]), nil
+	case Int32:
+		v, err := ReadUvarint(r)
+		return int32(v), err
+	case Uint32:
+		v, err := ReadUvarint(r)
+		return uint32(v), err
+	case Int64:
+		v, err := ReadUvarint(r)
+		return int64(v), err
+	case Uint64:
+		return ReadUvarint(r)
+	case Float:
+		var buff [4]byte
+		if _, err := io.ReadFull(r, buff[:]); err != nil {
+			return nil, err
+		}
+		return math.Float32frombits(binary.LittleEndian.Uint32(buff[:])), nil
+	case Double:
+		var buff [8]byte
+		if _, err := io.ReadFull(r, buff[:]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(buff[:])), nil
+	case Text, XML:
+		buff, err := readBytes(r)
+		return string(buff), err
+	case NText:
+		// Assumed layout, see the package documentation.
+		buff, err := readBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(buff)%2 != 0 {
+			return nil, fmt.Errorf("%w: odd NTEXT length", ErrInvalidData)
+		}
+		units := make([]uint16, len(buff)/2)
+		for idx := range units {
+			units[idx] = binary.LittleEndian.Uint16(buff[idx*2:])
+		}
+		return string(utf16.Decode(units)), nil
+	case Binary, JSON:
+		return readBytes(r)
+	case Date, Time, Timestamp:
+		return readTime(r, t, loc)
+	case UUID:
+		var v [16]byte
+		if _, err := io.ReadFull(r, v[:]); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case Struct:
+		return nil, fmt.Errorf("%w: %s values are decoded with DecodeStruct", ErrUnsupportedType, t)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+func typeError(t DataType, value interface{}) error {
+	return fmt.Errorf("codec: can't encode a value of type %T as %s", value, t)
+}
+
+// readBytes reads a varint length followed by as many bytes.
+func readBytes(r io.Reader) ([]byte, error) {
+
+	length, err := ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	buff := make([]byte, length)
+	if _, err = io.ReadFull(r, buff); err != nil {
+		return nil, err
+	}
+
+	return buff, nil
+}
+
+// ReadUvarint reads an unsigned varint. The reader is read one byte at a time
+// unless it is an io.ByteReader.
+func ReadUvarint(r io.Reader) (uint64, error) {
+
+	if br, ok := r.(io.ByteReader); ok {
+		return binary.ReadUvarint(br)
+	}
+
+	return binary.ReadUvarint(byteReader{r})
+}
+
+// ReadVarint reads a zigzag-encoded signed varint.
+func ReadVarint(r io.Reader) (int64, error) {
+
+	if br, ok := r.(io.ByteReader); ok {
+		return binary.ReadVarint(br)
+	}
+
+	return binary.ReadVarint(byteReader{r})
+}
+
+// WriteUvarint writes an unsigned varint.
+func WriteUvarint(w io.Writer, v uint64) error {
+
+	var buff [binary.MaxVarintLen64]byte
+	_, err := w.Write(buff[:binary.PutUvarint(buff[:], v)])
+
+	return err
+}
+
+// WriteVarint writes a zigzag-encoded signed varint.
+func WriteVarint(w io.Writer, v int64) error {
+
+	var buff [binary.MaxVarintLen64]byte
+	_, err := w.Write(buff[:binary.PutVarint(buff[:], v)])
+
+	return err
+}
+
+func appendUint16(buff []byte, v uint16) []byte {
+	return append(buff, byte(v), byte(v>>8))
+}
+
+func appendUint32(buff []byte, v uint32) []byte {
+	return append(buff, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(buff []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buff, uint32(v)), uint32(v>>32))
+}
+
+func appendUvarint(buff []byte, v uint64) []byte {
+
+	var encoded [binary.MaxVarintLen64]byte
+	return append(buff, encoded[:binary.PutUvarint(encoded[:], v)]...)
+}
+
+type byteReader struct {
+	io.Reader
+}
+
+func (r byteReader) ReadByte() (byte, error) {
+
+	var buff [1]byte
+	if _, err := io.ReadFull(r.Reader, buff[:]); err != nil {
+		return 0, err
+	}
+
+	return buff[0], nil
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+
+	loc := time.FixedZone("UTC+2", 2*3600)
+
+	tests := []struct {
+		dataType DataType
+		value    interface{}
+	}{
+		{Bool, true},
+		{Bool, false},
+		{Int8, int8(math.MinInt8)},
+		{Uint8, uint8(math.MaxUint8)},
+		{Int16, int16(math.MinInt16)},
+		{Uint16, uint16(math.MaxUint16)},
+		{Int32, int32(math.MinInt32)},
+		{Int32, int32(math.MaxInt32)},
+		{Uint32, uint32(math.MaxUint32)},
+		{Int64, int64(math.MinInt64)},
+		{Uint64, uint64(math.MaxUint64)},
+		{Float, float32(-1.5)},
+		{Double, math.Pi},
+		{Text, "Siodb ❤ Go"},
+		{Text, ""},
+		{NText, "汉字 𝄞"},
+		{Binary, []byte{0, 1, 2, 0xff}},
+		{Binary, []byte{}},
+		{Date, time.Date(2020, time.February, 29, 0, 0, 0, 0, loc)},
+		{Time, time.Date(0, time.January, 1, 23, 59, 58, 999999999, loc)},
+		{Timestamp, time.Date(1999, time.December, 31, 1, 2, 3, 4, loc)},
+		{XML, "<a b=\"c\"/>"},
+		{JSON, []byte(`{"a":[1,2]}`)},
+		{UUID, [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}},
+	}
+
+	for _, test := range tests {
+		var buff bytes.Buffer
+		if err := Encode(&buff, test.dataType, test.value); err != nil {
+			t.Fatalf("Encode(%s, %v): %v", test.dataType, test.value, err)
+		}
+		// Trailing byte to check that Decode reads exactly the value.
+		buff.WriteByte(0x2a)
+
+		value, err := Decode(&buff, test.dataType, loc)
+		if err != nil {
+			t.Fatalf("Decode(%s): %v", test.dataType, err)
+		}
+		if tm, ok := test.value.(time.Time); ok {
+			if !tm.Equal(value.(time.Time)) {
+				t.Fatalf("Decode(%s): %v != %v", test.dataType, value, tm)
+			}
+			_, expectedOffset := tm.Zone()
+			if _, offset := value.(time.Time).Zone(); offset != expectedOffset {
+				t.Fatalf("Decode(%s): offset %d != %d", test.dataType, offset, expectedOffset)
+			}
+		} else if !reflect.DeepEqual(value, test.value) {
+			t.Fatalf("Decode(%s): %#v != %#v", test.dataType, value, test.value)
+		}
+		if rest := buff.Bytes(); len(rest) != 1 || rest[0] != 0x2a {
+			t.Fatalf("Decode(%s): %d bytes left", test.dataType, len(rest))
+		}
+	}
+}
+
+func TestTimestampLayout(t *testing.T) {
+
+	var buff bytes.Buffer
+	ts := time.Date(2020, time.June, 1, 12, 30, 15, 500, time.UTC)
+	if err := Encode(&buff, Timestamp, ts); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	// 2020-06-01 is a Monday.
+	date := uint32(1) | uint32(time.Monday)<<1 | uint32(0)<<4 | uint32(5)<<9 | uint32(2020)<<13
+	tm := uint64(500)<<1 | uint64(15)<<31 | uint64(30)<<37 | uint64(12)<<43
+	expected := appendUint32(nil, date)
+	expected = append(expected, byte(tm), byte(tm>>8), byte(tm>>16), byte(tm>>24), byte(tm>>32), byte(tm>>40))
+
+	if !bytes.Equal(buff.Bytes(), expected) {
+		t.Fatalf("Encode: %x != %x", buff.Bytes(), expected)
+	}
+}
+
+func TestRowRoundTrip(t *testing.T) {
+
+	columns := []Column{
+		{Name: "ID", Type: Uint64},
+		{Name: "NAME", Type: Text, Nullable: true},
+		{Name: "ADDRESS", Type: Struct, Nullable: true, Attributes: []Column{
+			{Name: "CITY", Type: Text},
+			{Name: "ZIP", Type: Int32, Nullable: true},
+		}},
+	}
+	rows := [][]interface{}{
+		{uint64(1), "a", []interface{}{"Berlin", int32(10115)}},
+		{uint64(2), nil, []interface{}{"Paris", nil}},
+		{uint64(3), "c", nil},
+	}
+
+	var buff bytes.Buffer
+	for _, row := range rows {
+		if err := EncodeRow(&buff, columns, row); err != nil {
+			t.Fatalf("EncodeRow: %v", err)
+		}
+	}
+	if err := EncodeEndOfRows(&buff); err != nil {
+		t.Fatalf("EncodeEndOfRows: %v", err)
+	}
+
+	for _, expected := range rows {
+		row, err := DecodeRow(&buff, columns, nil)
+		if err != nil {
+			t.Fatalf("DecodeRow: %v", err)
+		}
+		if !reflect.DeepEqual(row, expected) {
+			t.Fatalf("DecodeRow: %#v != %#v", row, expected)
+		}
+	}
+	if _, err := DecodeRow(&buff, columns, nil); err != io.EOF {
+		t.Fatalf("DecodeRow: io.EOF expected, got %v", err)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+
+	var buff bytes.Buffer
+
+	if err := Encode(&buff, Int32, int64(1)); err == nil {
+		t.Fatalf("Encode: no error for a value of the wrong type")
+	}
+	if err := Encode(&buff, Struct, []interface{}{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("Encode: ErrUnsupportedType expected, got %v", err)
+	}
+	if err := EncodeRow(&buff, []Column{{Name: "ID", Type: Int32}}, []interface{}{nil}); err == nil {
+		t.Fatalf("EncodeRow: no error for a null value of a non-nullable column")
+	}
+	if _, err := Decode(bytes.NewReader([]byte{1, 0}), NText, nil); !errors.Is(err, ErrInvalidData) {
+		t.Fatalf("Decode: ErrInvalidData expected, got %v", err)
+	}
+	for _, dataType := range []DataType{TimeWithTZ, TimestampWithTZ, DateInterval, TimeInterval} {
+		if err := Encode(&buff, dataType, time.Now()); !errors.Is(err, ErrUnsupportedType) {
+			t.Fatalf("Encode(%s): ErrUnsupportedType expected, got %v", dataType, err)
+		}
+		if _, err := Decode(bytes.NewReader(make([]byte, 16)), dataType, nil); !errors.Is(err, ErrUnsupportedType) {
+			t.Fatalf("Decode(%s): ErrUnsupportedType expected, got %v", dataType, err)
+		}
+	}
+	if _, err := Decode(bytes.NewReader(nil), DataType(127), nil); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("Decode: ErrUnsupportedType expected, got %v", err)
+	}
+}
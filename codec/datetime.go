@@ -0,0 +1,124 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package codec
+
+import (
+	"io"
+	"time"
+)
+
+// The layout of the date and time parts is the one of the TIMESTAMP values
+// sent by Siodb.
+//
+// The date part is packed in 4 bytes, little endian:
+// hasTimePart (1 bit), dayOfWeek (3 bits), dayOfMonth-1 (5 bits), month-1 (4 bits), year (19 bits).
+//
+// The time part is packed in 6 bytes, little endian:
+// reserved (1 bit), nanos (30 bits), seconds (6 bits), minutes (6 bits), hours (5 bits).
+
+const (
+	datePartSize = 4
+	timePartSize = 6
+)
+
+// appendTime appends a date or time. Dates and times without time zone are
+// written with the wall clock of t, in its own location.
+func appendTime(buff []byte, t DataType, v time.Time) []byte {
+
+	switch t {
+	case Date:
+		buff = appendDatePart(buff, v, false)
+	case Time:
+		buff = appendTimePart(buff, v)
+	case Timestamp:
+		buff = appendDatePart(buff, v, true)
+		buff = appendTimePart(buff, v)
+	}
+
+	return buff
+}
+
+func appendDatePart(buff []byte, v time.Time, hasTimePart bool) []byte {
+
+	packed := uint32(v.Weekday())<<1 | uint32(v.Day()-1)<<4 | uint32(v.Month()-1)<<9 | uint32(v.Year())<<13
+	if hasTimePart {
+		packed |= 1
+	}
+
+	return appendUint32(buff, packed)
+}
+
+func appendTimePart(buff []byte, v time.Time) []byte {
+
+	packed := uint64(v.Nanosecond())<<1 | uint64(v.Second())<<31 | uint64(v.Minute())<<37 | uint64(v.Hour())<<43
+
+	return append(buff, byte(packed), byte(packed>>8), byte(packed>>16), byte(packed>>24), byte(packed>>32), byte(packed>>40))
+}
+
+// readTime reads a date or time. Dates and times without time zone are
+// returned in loc.
+func readTime(r io.Reader, t DataType, loc *time.Location) (time.Time, error) {
+
+	var year, day, hours, minutes, seconds, nanos int
+	var month time.Month
+	var hasTimePart bool
+	var err error
+
+	// Times are on 0000-01-01
+	year, month, day = 0, time.January, 1
+
+	if t == Date || t == Timestamp {
+		if year, month, day, hasTimePart, err = readDatePart(r); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	// A date is not expected to have a time part, but it must be consumed to stay in sync.
+	if hasTimePart || t == Time {
+		if hours, minutes, seconds, nanos, err = readTimePart(r); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if t == Date {
+		hours, minutes, seconds, nanos = 0, 0, 0, 0
+	}
+
+	return time.Date(year, month, day, hours, minutes, seconds, nanos, loc), nil
+}
+
+func readDatePart(r io.Reader) (year int, month time.Month, day int, hasTimePart bool, err error) {
+
+	var buff [datePartSize]byte
+	if _, err = io.ReadFull(r, buff[:]); err != nil {
+		return 0, 0, 0, false, err
+	}
+
+	packed := uint32(buff[0]) | uint32(buff[1])<<8 | uint32(buff[2])<<16 | uint32(buff[3])<<24
+	hasTimePart = packed&1 == 1
+	day = int(packed>>4&0x1f) + 1
+	month = time.Month(packed>>9&0xf) + 1
+	year = int(packed >> 13)
+
+	return year, month, day, hasTimePart, nil
+}
+
+func readTimePart(r io.Reader) (hours, minutes, seconds, nanos int, err error) {
+
+	var buff [timePartSize]byte
+	if _, err = io.ReadFull(r, buff[:]); err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	var packed uint64
+	for idx := timePartSize - 1; idx >= 0; idx-- {
+		packed = packed<<8 | uint64(buff[idx])
+	}
+	nanos = int(packed >> 1 & (1<<30 - 1))
+	seconds = int(packed >> 31 & 0x3f)
+	minutes = int(packed >> 37 & 0x3f)
+	hours = int(packed >> 43 & 0x1f)
+
+	return hours, minutes, seconds, nanos, nil
+}
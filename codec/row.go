@@ -0,0 +1,194 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Column describes a column of a result set or an attribute of a STRUCT value.
+type Column struct {
+	Name       string
+	Type       DataType
+	Nullable   bool
+	Attributes []Column // Attributes of STRUCT values
+}
+
+// NullBitmask flags the null values of a row or a STRUCT value, one bit per
+// column from the least significant bit of the first byte. It is sent only
+// when one of the columns is nullable.
+type NullBitmask []byte
+
+// NullBitmaskSize returns the size in bytes of the bitmask of n columns.
+func NullBitmaskSize(n int) int {
+	return (n + 7) / 8
+}
+
+// NewNullBitmask returns a bitmask of n columns without null value.
+func NewNullBitmask(n int) NullBitmask {
+	return make(NullBitmask, NullBitmaskSize(n))
+}
+
+// ReadNullBitmask reads the bitmask of n columns.
+func ReadNullBitmask(r io.Reader, n int) (NullBitmask, error) {
+
+	bitmask := NewNullBitmask(n)
+	if _, err := io.ReadFull(r, bitmask); err != nil {
+		return nil, err
+	}
+
+	return bitmask, nil
+}
+
+// IsNull reports whether the value of column idx is null.
+func (b NullBitmask) IsNull(idx int) bool {
+	return b != nil && b[idx/8]&byte(1<<(idx%8)) != 0
+}
+
+// SetNull flags the value of column idx as null.
+func (b NullBitmask) SetNull(idx int) {
+	b[idx/8] |= byte(1 << (idx % 8))
+}
+
+// hasNullable reports whether a null bitmask is sent for the columns.
+func hasNullable(columns []Column) bool {
+
+	for _, column := range columns {
+		if column.Nullable {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EncodeStruct writes a STRUCT value: a null bitmask when one attribute is
+// nullable, followed by the non-null attribute values in order. Nil values
+// are null and nested STRUCT values are []interface{}.
+func EncodeStruct(w io.Writer, attributes []Column, values []interface{}) error {
+
+	var buff bytes.Buffer
+	if err := encodeColumns(&buff, attributes, values); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buff.Bytes())
+	return err
+}
+
+// DecodeStruct reads a STRUCT value written by EncodeStruct.
+func DecodeStruct(r io.Reader, attributes []Column, loc *time.Location) ([]interface{}, error) {
+	return decodeColumns(r, attributes, loc)
+}
+
+// EncodeRow writes a row of a result set: its length, a null bitmask when
+// one column is nullable, followed by the non-null values in order.
+func EncodeRow(w io.Writer, columns []Column, values []interface{}) error {
+
+	var buff bytes.Buffer
+	if err := encodeColumns(&buff, columns, values); err != nil {
+		return err
+	}
+
+	if err := WriteUvarint(w, uint64(buff.Len())); err != nil {
+		return err
+	}
+	_, err := w.Write(buff.Bytes())
+
+	return err
+}
+
+// EncodeEndOfRows writes the empty row that ends a result set.
+func EncodeEndOfRows(w io.Writer) error {
+	return WriteUvarint(w, 0)
+}
+
+// DecodeRow reads a row written by EncodeRow. It returns io.EOF at the end of
+// the result set.
+func DecodeRow(r io.Reader, columns []Column, loc *time.Location) ([]interface{}, error) {
+
+	length, err := ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		return nil, io.EOF
+	}
+
+	return decodeColumns(r, columns, loc)
+}
+
+func encodeColumns(buff *bytes.Buffer, columns []Column, values []interface{}) error {
+
+	if len(values) != len(columns) {
+		return fmt.Errorf("codec: %d values for %d columns", len(values), len(columns))
+	}
+
+	if hasNullable(columns) {
+		bitmask := NewNullBitmask(len(columns))
+		for idx, value := range values {
+			if value == nil {
+				bitmask.SetNull(idx)
+			}
+		}
+		buff.Write(bitmask)
+	}
+
+	for idx, column := range columns {
+		if values[idx] == nil {
+			if !column.Nullable {
+				return fmt.Errorf("codec: null value for the non-nullable column %s", column.Name)
+			}
+			continue
+		}
+		if column.Type == Struct {
+			nested, ok := values[idx].([]interface{})
+			if !ok {
+				return typeError(column.Type, values[idx])
+			}
+			if err := encodeColumns(buff, column.Attributes, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := Encode(buff, column.Type, values[idx]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeColumns(r io.Reader, columns []Column, loc *time.Location) ([]interface{}, error) {
+
+	var bitmask NullBitmask
+	var err error
+
+	if hasNullable(columns) {
+		if bitmask, err = ReadNullBitmask(r, len(columns)); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	for idx, column := range columns {
+		if bitmask.IsNull(idx) {
+			continue
+		}
+		if column.Type == Struct {
+			values[idx], err = decodeColumns(r, column.Attributes, loc)
+		} else {
+			values[idx], err = Decode(r, column.Type, loc)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("codec: column %s: %w", column.Name, err)
+		}
+	}
+
+	return values, nil
+}
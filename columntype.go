@@ -0,0 +1,157 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	scanTypeBool      = reflect.TypeOf(false)
+	scanTypeInt8      = reflect.TypeOf(int8(0))
+	scanTypeUint8     = reflect.TypeOf(uint8(0))
+	scanTypeInt16     = reflect.TypeOf(int16(0))
+	scanTypeUint16    = reflect.TypeOf(uint16(0))
+	scanTypeInt32     = reflect.TypeOf(int32(0))
+	scanTypeUint32    = reflect.TypeOf(uint32(0))
+	scanTypeInt64     = reflect.TypeOf(int64(0))
+	scanTypeUint64    = reflect.TypeOf(uint64(0))
+	scanTypeFloat32   = reflect.TypeOf(float32(0))
+	scanTypeFloat64   = reflect.TypeOf(float64(0))
+	scanTypeString    = reflect.TypeOf("")
+	scanTypeBytes     = reflect.TypeOf([]byte(nil))
+	scanTypeTime      = reflect.TypeOf(time.Time{})
+	scanTypeInterval  = reflect.TypeOf(Interval{})
+	scanTypeXML       = reflect.TypeOf(XML(""))
+	scanTypeStruct    = reflect.TypeOf(Struct{})
+	scanTypeInterface = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// columnTypeName returns the Siodb SQL name of a column data type.
+func columnTypeName(columnType ColumnDataType) string {
+
+	switch columnType {
+	case ColumnDataType_COLUMN_DATA_TYPE_BOOL:
+		return "BOOLEAN"
+	case ColumnDataType_COLUMN_DATA_TYPE_INT8:
+		return "TINYINT"
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT8:
+		return "TINYUINT"
+	case ColumnDataType_COLUMN_DATA_TYPE_INT16:
+		return "SMALLINT"
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT16:
+		return "SMALLUINT"
+	case ColumnDataType_COLUMN_DATA_TYPE_INT32:
+		return "INT"
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT32:
+		return "UINT"
+	case ColumnDataType_COLUMN_DATA_TYPE_INT64:
+		return "BIGINT"
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT64:
+		return "BIGUINT"
+	case ColumnDataType_COLUMN_DATA_TYPE_FLOAT:
+		return "FLOAT"
+	case ColumnDataType_COLUMN_DATA_TYPE_DOUBLE:
+		return "DOUBLE"
+	case ColumnDataType_COLUMN_DATA_TYPE_TEXT:
+		return "TEXT"
+	case ColumnDataType_COLUMN_DATA_TYPE_NTEXT:
+		return "NTEXT"
+	case ColumnDataType_COLUMN_DATA_TYPE_BINARY:
+		return "BLOB"
+	case ColumnDataType_COLUMN_DATA_TYPE_DATE:
+		return "DATE"
+	case ColumnDataType_COLUMN_DATA_TYPE_TIME:
+		return "TIME"
+	case ColumnDataType_COLUMN_DATA_TYPE_TIME_WITH_TZ:
+		return "TIME WITH TIME ZONE"
+	case ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP:
+		return "TIMESTAMP"
+	case ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP_WITH_TZ:
+		return "TIMESTAMP WITH TIME ZONE"
+	case ColumnDataType_COLUMN_DATA_TYPE_DATE_INTERVAL:
+		return "DATE INTERVAL"
+	case ColumnDataType_COLUMN_DATA_TYPE_TIME_INTERVAL:
+		return "TIME INTERVAL"
+	case ColumnDataType_COLUMN_DATA_TYPE_STRUCT:
+		return "STRUCT"
+	case ColumnDataType_COLUMN_DATA_TYPE_XML:
+		return "XML"
+	case ColumnDataType_COLUMN_DATA_TYPE_JSON:
+		return "JSON"
+	case ColumnDataType_COLUMN_DATA_TYPE_UUID:
+		return "UUID"
+	default:
+		return ""
+	}
+}
+
+// columnScanType returns the Go type of the values returned for a column data type.
+func columnScanType(columnType ColumnDataType) reflect.Type {
+
+	switch columnType {
+	case ColumnDataType_COLUMN_DATA_TYPE_BOOL:
+		return scanTypeBool
+	case ColumnDataType_COLUMN_DATA_TYPE_INT8:
+		return scanTypeInt8
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT8:
+		return scanTypeUint8
+	case ColumnDataType_COLUMN_DATA_TYPE_INT16:
+		return scanTypeInt16
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT16:
+		return scanTypeUint16
+	case ColumnDataType_COLUMN_DATA_TYPE_INT32:
+		return scanTypeInt32
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT32:
+		return scanTypeUint32
+	case ColumnDataType_COLUMN_DATA_TYPE_INT64:
+		return scanTypeInt64
+	case ColumnDataType_COLUMN_DATA_TYPE_UINT64:
+		return scanTypeUint64
+	case ColumnDataType_COLUMN_DATA_TYPE_FLOAT:
+		return scanTypeFloat32
+	case ColumnDataType_COLUMN_DATA_TYPE_DOUBLE:
+		return scanTypeFloat64
+	case ColumnDataType_COLUMN_DATA_TYPE_TEXT,
+		ColumnDataType_COLUMN_DATA_TYPE_NTEXT,
+		ColumnDataType_COLUMN_DATA_TYPE_UUID:
+		return scanTypeString
+	case ColumnDataType_COLUMN_DATA_TYPE_BINARY,
+		ColumnDataType_COLUMN_DATA_TYPE_JSON:
+		return scanTypeBytes
+	case ColumnDataType_COLUMN_DATA_TYPE_DATE,
+		ColumnDataType_COLUMN_DATA_TYPE_TIME,
+		ColumnDataType_COLUMN_DATA_TYPE_TIME_WITH_TZ,
+		ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP,
+		ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP_WITH_TZ:
+		return scanTypeTime
+	case ColumnDataType_COLUMN_DATA_TYPE_DATE_INTERVAL,
+		ColumnDataType_COLUMN_DATA_TYPE_TIME_INTERVAL:
+		return scanTypeInterval
+	case ColumnDataType_COLUMN_DATA_TYPE_XML:
+		return scanTypeXML
+	case ColumnDataType_COLUMN_DATA_TYPE_STRUCT:
+		return scanTypeStruct
+	default:
+		return scanTypeInterface
+	}
+}
+
+// columnLength returns the maximum length of variable length column data types.
+func columnLength(columnType ColumnDataType) (length int64, ok bool) {
+
+	switch columnType {
+	case ColumnDataType_COLUMN_DATA_TYPE_TEXT,
+		ColumnDataType_COLUMN_DATA_TYPE_NTEXT,
+		ColumnDataType_COLUMN_DATA_TYPE_BINARY,
+		ColumnDataType_COLUMN_DATA_TYPE_XML,
+		ColumnDataType_COLUMN_DATA_TYPE_JSON:
+		return math.MaxInt64, true
+	default:
+		return 0, false
+	}
+}
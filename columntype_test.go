@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var columnTypeTests = []struct {
+	columnType ColumnDataType
+	name       string
+	scanType   reflect.Type
+	hasLength  bool
+}{
+	{ColumnDataType_COLUMN_DATA_TYPE_BOOL, "BOOLEAN", reflect.TypeOf(false), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_INT8, "TINYINT", reflect.TypeOf(int8(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_UINT8, "TINYUINT", reflect.TypeOf(uint8(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_INT16, "SMALLINT", reflect.TypeOf(int16(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_UINT16, "SMALLUINT", reflect.TypeOf(uint16(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_INT32, "INT", reflect.TypeOf(int32(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_UINT32, "UINT", reflect.TypeOf(uint32(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_INT64, "BIGINT", reflect.TypeOf(int64(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_UINT64, "BIGUINT", reflect.TypeOf(uint64(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_FLOAT, "FLOAT", reflect.TypeOf(float32(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_DOUBLE, "DOUBLE", reflect.TypeOf(float64(0)), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_TEXT, "TEXT", reflect.TypeOf(""), true},
+	{ColumnDataType_COLUMN_DATA_TYPE_NTEXT, "NTEXT", reflect.TypeOf(""), true},
+	{ColumnDataType_COLUMN_DATA_TYPE_BINARY, "BLOB", reflect.TypeOf([]byte(nil)), true},
+	{ColumnDataType_COLUMN_DATA_TYPE_DATE, "DATE", reflect.TypeOf(time.Time{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_TIME, "TIME", reflect.TypeOf(time.Time{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_TIME_WITH_TZ, "TIME WITH TIME ZONE", reflect.TypeOf(time.Time{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP, "TIMESTAMP", reflect.TypeOf(time.Time{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP_WITH_TZ, "TIMESTAMP WITH TIME ZONE", reflect.TypeOf(time.Time{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_DATE_INTERVAL, "DATE INTERVAL", reflect.TypeOf(Interval{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_TIME_INTERVAL, "TIME INTERVAL", reflect.TypeOf(Interval{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_STRUCT, "STRUCT", reflect.TypeOf(Struct{}), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_XML, "XML", reflect.TypeOf(XML("")), true},
+	{ColumnDataType_COLUMN_DATA_TYPE_JSON, "JSON", reflect.TypeOf([]byte(nil)), true},
+	{ColumnDataType_COLUMN_DATA_TYPE_UUID, "UUID", reflect.TypeOf(""), false},
+	{ColumnDataType_COLUMN_DATA_TYPE_UNKNOWN, "", reflect.TypeOf((*interface{})(nil)).Elem(), false},
+}
+
+func TestColumnTypes(t *testing.T) {
+
+	tested := make(map[ColumnDataType]bool)
+
+	for _, test := range columnTypeTests {
+		tested[test.columnType] = true
+
+		if name := columnTypeName(test.columnType); name != test.name {
+			t.Fatalf("columnTypeName(%s): %q != %q", test.columnType, name, test.name)
+		}
+		if scanType := columnScanType(test.columnType); scanType != test.scanType {
+			t.Fatalf("columnScanType(%s): %s != %s", test.columnType, scanType, test.scanType)
+		}
+		length, ok := columnLength(test.columnType)
+		if ok != test.hasLength || (ok && length != math.MaxInt64) || (!ok && length != 0) {
+			t.Fatalf("columnLength(%s): %d, %t", test.columnType, length, ok)
+		}
+	}
+
+	for columnType := ColumnDataType(0); columnType < ColumnDataType_COLUMN_DATA_TYPE_MAX; columnType++ {
+		if !tested[columnType] {
+			t.Fatalf("TestColumnTypes: no test for %s", columnType)
+		}
+	}
+}
+
+func TestRowsColumnTypes(t *testing.T) {
+
+	rows := &siodbRows{
+		sc: &siodbConn{},
+		columnDesc: []*ColumnDescription{
+			{Name: "ID", Type: ColumnDataType_COLUMN_DATA_TYPE_UINT64},
+			{Name: "NAME", Type: ColumnDataType_COLUMN_DATA_TYPE_TEXT, IsNull: true},
+			{Name: "DATA", Type: ColumnDataType_COLUMN_DATA_TYPE_BINARY},
+			{Name: "CREATED", Type: ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP, IsNull: true},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		scanType  reflect.Type
+		nullable  bool
+		hasLength bool
+	}{
+		{"BIGUINT", reflect.TypeOf(uint64(0)), false, false},
+		{"TEXT", reflect.TypeOf(""), true, true},
+		{"BLOB", reflect.TypeOf([]byte(nil)), false, true},
+		{"TIMESTAMP", reflect.TypeOf(time.Time{}), true, false},
+	}
+
+	for idx, test := range tests {
+		if name := rows.ColumnTypeDatabaseTypeName(idx); name != test.name {
+			t.Fatalf("ColumnTypeDatabaseTypeName(%d): %q != %q", idx, name, test.name)
+		}
+		if scanType := rows.ColumnTypeScanType(idx); scanType != test.scanType {
+			t.Fatalf("ColumnTypeScanType(%d): %s != %s", idx, scanType, test.scanType)
+		}
+		if nullable, ok := rows.ColumnTypeNullable(idx); !ok || nullable != test.nullable {
+			t.Fatalf("ColumnTypeNullable(%d): %t, %t", idx, nullable, ok)
+		}
+		if length, ok := rows.ColumnTypeLength(idx); ok != test.hasLength || (ok && length != math.MaxInt64) {
+			t.Fatalf("ColumnTypeLength(%d): %d, %t", idx, length, ok)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Default maximum size of the text of a command, in bytes.
+const defaultMaxCommandSize = 1 << 30
+
+// Number of the text field of Command, appended to the encoded command while
+// the text is streamed.
+var commandTextField = proto.MessageReflect(&Command{}).Descriptor().Fields().ByName("text").Number()
+
+// Size of the chunks read from binary parameters while the command is sent.
+const blobChunkSize = 32 * 1024
+
+// BlobSource is a binary parameter of known size. It is read and sent as a
+// hexadecimal literal while the command is written, without being loaded in
+// memory. Other io.Reader parameters are read in memory before the command
+// is sent, their size being needed first.
+type BlobSource interface {
+	io.Reader
+	Size() int64
+}
+
+// NewBlobSource returns a BlobSource of size bytes read from r.
+func NewBlobSource(r io.Reader, size int64) BlobSource {
+	return &blobSource{r: r, size: size}
+}
+
+type blobSource struct {
+	r    io.Reader
+	size int64
+}
+
+func (bs *blobSource) Read(p []byte) (int, error) {
+	return bs.r.Read(p)
+}
+
+func (bs *blobSource) Size() int64 {
+	return bs.size
+}
+
+// commandText is the text of a command with the binary parameters read from
+// readers: chunks[i] is followed by readers[i], the last chunk ends the text.
+type commandText struct {
+	chunks  []string
+	readers []io.Reader
+}
+
+// size returns the size of the text once the readers are resolved.
+func (ct *commandText) size() int64 {
+
+	var size int64
+	for _, chunk := range ct.chunks {
+		size += int64(len(chunk))
+	}
+	for _, r := range ct.readers {
+		// x'...'
+		size += 3 + 2*r.(BlobSource).Size()
+	}
+
+	return size
+}
+
+// resolveReaders reads in memory the readers of unknown size, up to the
+// maximum size of the command.
+func (ct *commandText) resolveReaders(maxSize int64) error {
+
+	for idx, r := range ct.readers {
+		if _, ok := r.(BlobSource); ok {
+			continue
+		}
+		if maxSize >= 0 {
+			r = io.LimitReader(r, maxSize/2+1)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return &DriverError{Message: "Fail to read a binary parameter | " + err.Error(), Err: err}
+		}
+		ct.readers[idx] = NewBlobSource(bytes.NewReader(data), int64(len(data)))
+	}
+
+	return nil
+}
+
+// maxCommandSize returns the maximum size of a command, negative if unlimited.
+func (sc *siodbConn) maxCommandSize() int64 {
+
+	if sc.cfg.MaxCommandSize == 0 {
+		return defaultMaxCommandSize
+	}
+
+	return sc.cfg.MaxCommandSize
+}
+
+// writeCommand sends a command, reading its binary parameters while it is
+// written. A failure before anything is sent leaves the connection usable.
+// A write failure is returned as driver.ErrBadConn only when the command has
+// no reader, since readers can't be read again if the command is retried.
+func (sc *siodbConn) writeCommand(ct *commandText) error {
+
+	maxSize := sc.maxCommandSize()
+	if err := ct.resolveReaders(maxSize); err != nil {
+		return err
+	}
+
+	textSize := ct.size()
+	if maxSize >= 0 && textSize > maxSize {
+		return &DriverError{Message: fmt.Sprintf("Command of %d bytes exceeds the maximum size of %d bytes.", textSize, maxSize), Err: ErrCommandTooLarge}
+	}
+
+	sc.RequestID = 1
+
+	if len(ct.readers) == 0 && textSize < 300 {
+		sc.debug("writeServerCommand | Message to send: RequestID: %d Text: %q", sc.RequestID, ct.chunks[0])
+	} else {
+		sc.debug("writeServerCommand | Message too big to dump (%d bytes).", textSize)
+	}
+
+	// The Command message is encoded without its text, whose field is then
+	// appended and streamed from the chunks and readers.
+	header, err := proto.Marshal(&Command{RequestID: sc.RequestID})
+	if err != nil {
+		return &DriverError{Message: "Fail to encode the command | " + err.Error(), Err: err}
+	}
+	if textSize > 0 {
+		header = protowire.AppendTag(header, commandTextField, protowire.BytesType)
+		header = protowire.AppendVarint(header, uint64(textSize))
+	}
+
+	var prefix []byte
+	prefix = appendUvarint(prefix, 1)
+	prefix = appendUvarint(prefix, uint64(int64(len(header))+textSize))
+	prefix = append(prefix, header...)
+
+	writeFailed := func(err error) error {
+		sc.bad = true
+		if len(ct.readers) == 0 {
+			return &badConnError{err}
+		}
+		return &DriverError{Message: "Fail to send the command | " + err.Error(), Err: err}
+	}
+
+	if _, err := sc.w.Write(prefix); err != nil {
+		return writeFailed(err)
+	}
+
+	for idx, chunk := range ct.chunks {
+		if _, err := io.WriteString(sc.w, chunk); err != nil {
+			return writeFailed(err)
+		}
+		if idx < len(ct.readers) {
+			if err := sc.writeHexLiteral(ct.readers[idx].(BlobSource)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := sc.w.Flush(); err != nil {
+		return writeFailed(err)
+	}
+
+	return nil
+}
+
+// writeHexLiteral sends a binary parameter as an x'...' literal.
+func (sc *siodbConn) writeHexLiteral(bs BlobSource) error {
+
+	if _, err := io.WriteString(sc.w, "x'"); err != nil {
+		sc.bad = true
+		return &DriverError{Message: "Fail to send the command | " + err.Error(), Err: err}
+	}
+
+	buff := make([]byte, blobChunkSize)
+	encoded := make([]byte, hex.EncodedLen(blobChunkSize))
+	remaining := bs.Size()
+
+	for remaining > 0 {
+		chunk := buff
+		if remaining < int64(len(chunk)) {
+			chunk = chunk[:remaining]
+		}
+		n, err := io.ReadFull(bs, chunk)
+		if err != nil {
+			// The command is partially sent.
+			sc.bad = true
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return &DriverError{Message: fmt.Sprintf("Binary parameter is %d bytes shorter than its size of %d bytes.", remaining-int64(n), bs.Size()), Err: err}
+			}
+			return &DriverError{Message: "Fail to read a binary parameter | " + err.Error(), Err: err}
+		}
+		hex.Encode(encoded, chunk)
+		if _, err = sc.w.Write(encoded[:hex.EncodedLen(n)]); err != nil {
+			sc.bad = true
+			return &DriverError{Message: "Fail to send the command | " + err.Error(), Err: err}
+		}
+		remaining -= int64(n)
+	}
+
+	if _, err := io.WriteString(sc.w, "'"); err != nil {
+		sc.bad = true
+		return &DriverError{Message: "Fail to send the command | " + err.Error(), Err: err}
+	}
+
+	return nil
+}
+
+func appendUvarint(buff []byte, v uint64) []byte {
+
+	var encoded [binary.MaxVarintLen64]byte
+	return append(buff, encoded[:binary.PutUvarint(encoded[:], v)]...)
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// recordConn records what is written to the connection.
+type recordConn struct {
+	net.Conn
+	buf    bytes.Buffer
+	writes int
+}
+
+func (rc *recordConn) Write(p []byte) (int, error) {
+	rc.writes++
+	return rc.buf.Write(p)
+}
+
+// readCommand decodes the command written to the connection.
+func readCommand(t *testing.T, rc *recordConn) *Command {
+
+	var command Command
+	if _, err := newStreamConn(rc.buf.Bytes()).ReadMessage(1, &command); err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+
+	return &command
+}
+
+func TestWriteCommandEncoding(t *testing.T) {
+
+	for _, text := range []string{"", "SELECT 1", strings.Repeat("x", 300)} {
+		rc := &recordConn{}
+		sc := newConn(rc, Config{})
+		if err := sc.writeServerCommand(text); err != nil {
+			t.Fatalf("writeServerCommand: %v", err)
+		}
+
+		expected, err := appendMessage(nil, 1, &Command{RequestID: 1, Text: text})
+		if err != nil {
+			t.Fatalf("appendMessage: %v", err)
+		}
+		if !bytes.Equal(rc.buf.Bytes(), expected) {
+			t.Fatalf("writeServerCommand(%q): %x != %x", text, rc.buf.Bytes(), expected)
+		}
+		if rc.writes != 1 {
+			t.Fatalf("writeServerCommand(%q): sent in %d writes", text, rc.writes)
+		}
+	}
+}
+
+func TestWriteCommandReaders(t *testing.T) {
+
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: bytes.NewReader([]byte{0xca, 0xfe})},
+		{Ordinal: 2, Value: NewBlobSource(strings.NewReader("\x01\x02"), 2)},
+		{Ordinal: 3, Value: int64(1)},
+	}
+	ct, err := parseQuery("INSERT INTO t VALUES (?, ?, ?)").bindCommand(args, time.UTC)
+	if err != nil {
+		t.Fatalf("bindCommand: %v", err)
+	}
+
+	rc := &recordConn{}
+	sc := newConn(rc, Config{})
+	if err = sc.writeCommand(ct); err != nil {
+		t.Fatalf("writeCommand: %v", err)
+	}
+
+	command := readCommand(t, rc)
+	if expected := "INSERT INTO t VALUES (x'cafe', x'0102', 1)"; command.Text != expected || command.RequestID != 1 {
+		t.Fatalf("writeCommand: %v != %q", proto.CompactTextString(command), expected)
+	}
+}
+
+func TestWriteCommandUnmarshal(t *testing.T) {
+
+	blob := bytes.Repeat([]byte{0xab}, 2*blobChunkSize+1)
+	args := []driver.NamedValue{{Ordinal: 1, Value: NewBlobSource(bytes.NewReader(blob), int64(len(blob)))}}
+	ct, err := parseQuery("INSERT INTO t VALUES (?)").bindCommand(args, time.UTC)
+	if err != nil {
+		t.Fatalf("bindCommand: %v", err)
+	}
+
+	rc := &recordConn{}
+	sc := newConn(rc, Config{})
+	if err = sc.writeCommand(ct); err != nil {
+		t.Fatalf("writeCommand: %v", err)
+	}
+
+	// The message must be the whole output and decode as a Command.
+	rsc := newStreamConn(rc.buf.Bytes())
+	if id, err := rsc.readVarint(); err != nil || id != 1 {
+		t.Fatalf("readVarint: %d, %v", id, err)
+	}
+	length, err := rsc.readVarint()
+	if err != nil {
+		t.Fatalf("readVarint: %v", err)
+	}
+	message, err := ioutil.ReadAll(rsc.r)
+	if err != nil || uint64(len(message)) != length {
+		t.Fatalf("message: %d bytes != %d, %v", len(message), length, err)
+	}
+
+	var command Command
+	if err = proto.Unmarshal(message, &command); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if expected := "INSERT INTO t VALUES (x'" + hex.EncodeToString(blob) + "')"; command.Text != expected || command.RequestID != 1 {
+		t.Fatalf("writeCommand: unexpected RequestID %d, text of %d bytes", command.RequestID, len(command.Text))
+	}
+}
+
+func TestWriteCommandTooLarge(t *testing.T) {
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: bytes.NewReader(make([]byte, 100))}}
+	ct, err := parseQuery("SELECT ?").bindCommand(args, time.UTC)
+	if err != nil {
+		t.Fatalf("bindCommand: %v", err)
+	}
+
+	rc := &recordConn{}
+	sc := newConn(rc, Config{MaxCommandSize: 100})
+	if err = sc.writeCommand(ct); !errors.Is(err, ErrCommandTooLarge) {
+		t.Fatalf("writeCommand: ErrCommandTooLarge expected, got %v", err)
+	}
+	if rc.buf.Len() != 0 || sc.bad {
+		t.Fatalf("writeCommand: %d bytes sent, bad=%t", rc.buf.Len(), sc.bad)
+	}
+}
+
+func TestWriteCommandShortBlobSource(t *testing.T) {
+
+	args := []driver.NamedValue{{Ordinal: 1, Value: NewBlobSource(strings.NewReader("abc"), 10)}}
+	ct, err := parseQuery("SELECT ?").bindCommand(args, time.UTC)
+	if err != nil {
+		t.Fatalf("bindCommand: %v", err)
+	}
+
+	sc := newConn(&recordConn{}, Config{})
+	if err = sc.writeCommand(ct); err == nil || errors.Is(err, driver.ErrBadConn) {
+		t.Fatalf("writeCommand: unexpected %v", err)
+	}
+	if !sc.bad {
+		t.Fatalf("writeCommand: the connection is not marked bad")
+	}
+}
@@ -5,33 +5,122 @@
 package siodb
 
 import (
+	"bufio"
 	"context"
 	"database/sql/driver"
 	"net"
 )
 
-type connector struct {
-	cfg Config // immutable private copy.
-}
-
 type siodbConn struct {
 	netConn             net.Conn
+	r                   *bufio.Reader
+	w                   *bufio.Writer
 	cfg                 Config
 	sessionID           string
 	RequestID           uint64
 	nullAllowed         bool
 	nullBitmaskByteSize int
 	completed           bool
+	responseID          uint32
+	responseCount       uint32
+	bad                 bool
+	tx                  *siodbTx
+	stmtCache           map[string]*parsedQuery
 }
 
-// BeginTx TODO: https://golang.org/pkg/database/sql/driver/#ConnBeginTx
-func BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
-	return nil, nil
+// Maximum number of parsed statements cached per connection.
+const stmtCacheSize = 256
+
+// Size of the read and write buffers of a connection.
+const connBufferSize = 64 * 1024
+
+// newConn returns a connection reading and writing netConn through buffers.
+func newConn(netConn net.Conn, cfg Config) *siodbConn {
+	return &siodbConn{
+		netConn: netConn,
+		r:       bufio.NewReaderSize(netConn, connBufferSize),
+		w:       bufio.NewWriterSize(netConn, connBufferSize),
+		cfg:     cfg,
+	}
+}
+
+// BeginTx starts a transaction. See https://golang.org/pkg/database/sql/driver/#ConnBeginTx
+func (sc *siodbConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+
+	var err error
+
+	if sc.bad {
+		return nil, driver.ErrBadConn
+	}
+
+	if sc.tx != nil {
+		return nil, &DriverError{Message: "A transaction is already in progress on this connection."}
+	}
+
+	if err = checkTxOptions(opts); err != nil {
+		return nil, err
+	}
+
+	watcher, err := sc.startWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.stop()
+
+	if err = sc.execTxCommand("BEGIN TRANSACTION"); err != nil {
+		return nil, watcher.check(err)
+	}
+
+	sc.tx = &siodbTx{
+		sc:       sc,
+		readOnly: opts.ReadOnly,
+	}
+
+	return sc.tx, nil
 }
 
-// Begin TODO: https://golang.org/pkg/database/sql/driver/#Conn
+// Begin starts a transaction with the default options. See https://golang.org/pkg/database/sql/driver/#Conn
 func (sc *siodbConn) Begin() (driver.Tx, error) {
-	return nil, nil
+	return sc.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (sc *siodbConn) checkReadOnly(query string) error {
+
+	if sc.tx != nil && sc.tx.readOnly && !isReadOnlyStatement(query) {
+		return &DriverError{Message: "Statement not allowed in a read-only transaction."}
+	}
+
+	return nil
+}
+
+// IsValid reports whether the connection can be reused.
+// See https://golang.org/pkg/database/sql/driver/#Validator
+func (sc *siodbConn) IsValid() bool {
+	return !sc.bad
+}
+
+// ResetSession drops what is left of a partially read result before the
+// connection is reused. See https://golang.org/pkg/database/sql/driver/#SessionResetter
+func (sc *siodbConn) ResetSession(ctx context.Context) error {
+
+	if sc.bad {
+		return driver.ErrBadConn
+	}
+
+	if !sc.completed || sc.moreResponses() {
+		watcher, err := sc.startWatcher(ctx)
+		if err != nil {
+			return err
+		}
+		defer watcher.stop()
+
+		if _, err = sc.discardResponses(); err != nil {
+			sc.debug("ResetSession | Unable to drop the pending rows: %v.", watcher.check(err))
+			return driver.ErrBadConn
+		}
+	}
+
+	return nil
 }
 
 // Close TODO: Implement proper exit in Siodb
@@ -43,21 +132,51 @@ func (sc *siodbConn) Close() (err error) {
 	return nil
 }
 
-// PrepareContext TODO: https://golang.org/pkg/database/sql/driver/#ConnPrepareContext
-func PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	return nil, nil
+// PrepareContext returns a statement bound to the connection. Siodb has no
+// server-side prepared statements: the statement is parsed for its
+// placeholders once and the arguments are bound at each execution.
+// See https://golang.org/pkg/database/sql/driver/#ConnPrepareContext
+func (sc *siodbConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &siodbStmt{
+		sc: sc,
+		pq: sc.parseQuery(query),
+	}, nil
 }
 
-// Prepare TODO: https://golang.org/pkg/database/sql/driver/#Conn
+// Prepare See https://golang.org/pkg/database/sql/driver/#Conn
 func (sc *siodbConn) Prepare(query string) (driver.Stmt, error) {
-	return nil, nil
+	return sc.PrepareContext(context.Background(), query)
+}
+
+// parseQuery returns the parsed statement from the connection cache.
+// The cache is emptied when full to keep its size bounded.
+func (sc *siodbConn) parseQuery(query string) *parsedQuery {
+
+	if pq, ok := sc.stmtCache[query]; ok {
+		return pq
+	}
+
+	if sc.stmtCache == nil || len(sc.stmtCache) >= stmtCacheSize {
+		sc.stmtCache = make(map[string]*parsedQuery)
+	}
+	pq := parseQuery(query)
+	sc.stmtCache[query] = pq
+
+	return pq
 }
 
 func checkServerError(Message []*StatusMessage) error {
 
 	if len(Message) > 0 {
-		for _, Msg := range Message {
-			return &siodbServerError{Msg.StatusCode, Msg.Text}
+		return &ServerError{
+			Code:     Message[0].GetStatusCode(),
+			Message:  Message[0].GetText(),
+			Messages: Message,
 		}
 	}
 
@@ -65,23 +184,53 @@ func checkServerError(Message []*StatusMessage) error {
 }
 
 func (sc *siodbConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return sc.exec(ctx, sc.parseQuery(query), args)
+}
+
+func (sc *siodbConn) exec(ctx context.Context, pq *parsedQuery, args []driver.NamedValue) (driver.Result, error) {
 
 	var sr ServerResponse
+	var ct *commandText
 	var err error
 
-	// TODO: Bind Values
+	if sc.bad {
+		return nil, driver.ErrBadConn
+	}
+
+	if ct, err = pq.bindCommand(args, sc.location()); err != nil {
+		return nil, err
+	}
 
-	if err = sc.writeServerCommand(query); err != nil {
-		return nil, &siodbDriverError{"Fail to write server command."}
+	if err = sc.checkReadOnly(pq.query); err != nil {
+		return nil, err
+	}
+
+	watcher, err := sc.startWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer watcher.stop()
+
+	if err = sc.writeCommand(ct); err != nil {
+		return nil, watcher.check(err)
 	}
 
 	if sr, err = sc.readServer(); err != nil {
-		return nil, &siodbDriverError{"Fail to read server response."}
+		return nil, watcher.check(&DriverError{Message: "Fail to read server response: " + err.Error()})
+	}
+
+	// Read through the other responses if the command had several statements.
+	serverErr, err := sc.discardResponses()
+	if err != nil {
+		return nil, watcher.check(&DriverError{Message: "Fail to read server response: " + err.Error()})
 	}
 
 	if err = checkServerError(sr.Message); err != nil {
 		return nil, err
 	}
+	if serverErr != nil {
+		return nil, serverErr
+	}
 
 	var AffectedRowCount int64
 	if sr.HasAffectedRowCount {
@@ -95,28 +244,59 @@ func (sc *siodbConn) ExecContext(ctx context.Context, query string, args []drive
 }
 
 func (sc *siodbConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return sc.query(ctx, sc.parseQuery(query), args)
+}
+
+func (sc *siodbConn) query(ctx context.Context, pq *parsedQuery, args []driver.NamedValue) (driver.Rows, error) {
 
 	var sr ServerResponse
+	var ct *commandText
 	var err error
 
-	// TODO: Bind Values
+	if sc.bad {
+		return nil, driver.ErrBadConn
+	}
+
+	if ct, err = pq.bindCommand(args, sc.location()); err != nil {
+		return nil, err
+	}
+
+	if err = sc.checkReadOnly(pq.query); err != nil {
+		return nil, err
+	}
+
+	watcher, err := sc.startWatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	if err = sc.writeServerCommand(query); err != nil {
+	if err = sc.writeCommand(ct); err != nil {
+		err = watcher.check(err)
+		watcher.stop()
 		return nil, err
 	}
 
 	if sr, err = sc.readServer(); err != nil {
+		err = watcher.check(err)
+		watcher.stop()
 		return nil, err
 	}
 
 	if err = checkServerError(sr.Message); err != nil {
+		// Read through the other responses if the command had several statements.
+		if _, discardErr := sc.discardResponses(); discardErr != nil {
+			sc.bad = true
+			watcher.check(discardErr)
+		}
+		watcher.stop()
 		return nil, err
 	}
 
-	// Init rows struct for further next()
+	// Init rows struct for further next(), the watcher runs until the rows are closed.
 	rows := new(siodbRows)
 	rows.sc = sc
 	rows.columnDesc = sr.ColumnDescription
+	rows.watcher = watcher
 
 	return rows, err
 }
@@ -0,0 +1,285 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+)
+
+func TestResetSessionDrainsRows(t *testing.T) {
+
+	client, server := net.Pipe()
+	defer server.Close()
+	go func() {
+		// Two rows of 2 bytes then the end of the dataset.
+		server.Write([]byte{2, 0xAA, 0xBB, 2, 0xCC, 0xDD, 0})
+		server.Close()
+	}()
+
+	sc := newConn(client, Config{})
+	if err := sc.ResetSession(context.Background()); err != nil {
+		t.Fatalf("ResetSession: unexpected error %v", err)
+	}
+	if !sc.completed || !sc.IsValid() {
+		t.Fatalf("ResetSession: completed=%t valid=%t", sc.completed, sc.IsValid())
+	}
+}
+
+func TestResetSessionBadConn(t *testing.T) {
+
+	client, server := net.Pipe()
+	go func() {
+		// Row announced but stream cut.
+		server.Write([]byte{8, 0xAA})
+		server.Close()
+	}()
+
+	sc := newConn(client, Config{})
+	if err := sc.ResetSession(context.Background()); err != driver.ErrBadConn {
+		t.Fatalf("ResetSession: unexpected error %v", err)
+	}
+	if sc.IsValid() {
+		t.Fatalf("IsValid: poisoned connection reported as valid")
+	}
+	if _, err := sc.ExecContext(context.Background(), "DELETE FROM test.t", nil); err != driver.ErrBadConn {
+		t.Fatalf("ExecContext: unexpected error %v", err)
+	}
+}
+
+// newFakeServerConn returns a connection to a fake server which swallows the
+// commands and answers with the given stream once the first command arrived.
+func newFakeServerConn(t *testing.T, stream []byte) *siodbConn {
+
+	client, server := net.Pipe()
+	go func() {
+		var buf [1]byte
+		if _, err := server.Read(buf[:]); err != nil {
+			return
+		}
+		go io.Copy(ioutil.Discard, server)
+		server.Write(stream)
+	}()
+
+	sc := newConn(client, Config{})
+	sc.completed = true
+
+	return sc
+}
+
+// appendResponse appends a server response message to the stream.
+func appendResponse(t *testing.T, stream []byte, sr *ServerResponse) []byte {
+
+	stream, err := appendMessage(stream, 2, sr)
+	if err != nil {
+		t.Fatalf("appendMessage: %v", err)
+	}
+
+	return stream
+}
+
+func TestMultipleResultSets(t *testing.T) {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:     1,
+		ResponseCount: 2,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "A", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+		},
+	})
+	stream = append(stream, 1, 42, 0)
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:  1,
+		ResponseId: 1,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "B", Type: ColumnDataType_COLUMN_DATA_TYPE_TEXT, IsNull: true},
+		},
+	})
+	stream = append(stream, 3, 0, 1, 'x', 1, 1, 0)
+
+	sc := newFakeServerConn(t, stream)
+	driverRows, err := sc.QueryContext(context.Background(), "SELECT a FROM t1; SELECT b FROM t2", nil)
+	if err != nil {
+		t.Fatalf("QueryContext: %v", err)
+	}
+	rows := driverRows.(*siodbRows)
+
+	dest := make([]driver.Value, 1)
+	if err = rows.Next(dest); err != nil || dest[0] != int64(42) {
+		t.Fatalf("Next: %v, %v", dest[0], err)
+	}
+	if !rows.HasNextResultSet() {
+		t.Fatalf("HasNextResultSet: second dataset not reported")
+	}
+	if err = rows.NextResultSet(); err != nil {
+		t.Fatalf("NextResultSet: %v", err)
+	}
+	if columns := rows.Columns(); len(columns) != 1 || columns[0] != "B" {
+		t.Fatalf("Columns: %v", columns)
+	}
+	if err = rows.Next(dest); err != nil || dest[0] != "x" {
+		t.Fatalf("Next: %v, %v", dest[0], err)
+	}
+	if err = rows.Next(dest); err != nil || dest[0] != nil {
+		t.Fatalf("Next: %v, %v", dest[0], err)
+	}
+	if err = rows.Next(dest); err != io.EOF {
+		t.Fatalf("Next: expected io.EOF, got %v", err)
+	}
+	if rows.HasNextResultSet() {
+		t.Fatalf("HasNextResultSet: no more dataset expected")
+	}
+	if err = rows.NextResultSet(); err != io.EOF {
+		t.Fatalf("NextResultSet: expected io.EOF, got %v", err)
+	}
+	if err = rows.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestMixedResultSets(t *testing.T) {
+
+	dataset := &ServerResponse{
+		RequestID: 1,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "A", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+		},
+	}
+	noDataset := &ServerResponse{
+		RequestID:        1,
+		AffectedRowCount: 3,
+	}
+
+	tests := []struct {
+		query     string
+		responses []*ServerResponse
+	}{
+		{"SELECT a FROM t1; DELETE FROM t2", []*ServerResponse{dataset, noDataset}},
+		{"DELETE FROM t2; SELECT a FROM t1", []*ServerResponse{noDataset, dataset}},
+	}
+
+	for _, test := range tests {
+		var stream []byte
+		for idx, response := range test.responses {
+			sr := *response
+			sr.ResponseId = uint32(idx)
+			if idx == 0 {
+				sr.ResponseCount = uint32(len(test.responses))
+			}
+			stream = appendResponse(t, stream, &sr)
+			if len(sr.ColumnDescription) > 0 {
+				stream = append(stream, 1, 42, 0)
+			}
+		}
+
+		db := sql.OpenDB(connConnector{newFakeServerConn(t, stream)})
+
+		rows, err := db.QueryContext(context.Background(), test.query)
+		if err != nil {
+			t.Fatalf("QueryContext(%q): %v", test.query, err)
+		}
+		var values []int64
+		for {
+			for rows.Next() {
+				var a int64
+				if err = rows.Scan(&a); err != nil {
+					t.Fatalf("Scan(%q): %v", test.query, err)
+				}
+				values = append(values, a)
+			}
+			if !rows.NextResultSet() {
+				break
+			}
+		}
+		if err = rows.Err(); err != nil {
+			t.Fatalf("Err(%q): %v", test.query, err)
+		}
+		if len(values) != 1 || values[0] != 42 {
+			t.Fatalf("Next(%q): unexpected values %v", test.query, values)
+		}
+		if err = rows.Close(); err != nil {
+			t.Fatalf("Close(%q): %v", test.query, err)
+		}
+		db.Close()
+	}
+}
+
+func TestQueryErrorDiscardsResponses(t *testing.T) {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:     1,
+		ResponseCount: 2,
+		Message:       []*StatusMessage{{StatusCode: 7, Text: "Table T1 does not exist"}},
+	})
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:  1,
+		ResponseId: 1,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "B", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+		},
+	})
+	stream = append(stream, 1, 42, 0)
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID:           1,
+		HasAffectedRowCount: true,
+		AffectedRowCount:    3,
+	})
+
+	sc := newFakeServerConn(t, stream)
+	if _, err := sc.QueryContext(context.Background(), "SELECT a FROM t1; SELECT b FROM t2", nil); err == nil {
+		t.Fatalf("QueryContext: server error expected")
+	}
+	if sc.moreResponses() || !sc.completed || !sc.IsValid() {
+		t.Fatalf("QueryContext: responses left on the connection")
+	}
+
+	// The next command reads its own response.
+	result, err := sc.ExecContext(context.Background(), "DELETE FROM t3", nil)
+	if err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+	if affected, _ := result.RowsAffected(); affected != 3 {
+		t.Fatalf("ExecContext: %d rows affected instead of 3", affected)
+	}
+}
+
+func TestQueryErrorDiscardFailure(t *testing.T) {
+
+	stream := appendResponse(t, nil, &ServerResponse{
+		RequestID:     1,
+		ResponseCount: 2,
+		Message:       []*StatusMessage{{StatusCode: 7, Text: "Table T1 does not exist"}},
+	})
+
+	// The connection ends before the second response.
+	client, server := net.Pipe()
+	go func() {
+		var buf [1]byte
+		if _, err := server.Read(buf[:]); err != nil {
+			return
+		}
+		go io.Copy(ioutil.Discard, server)
+		server.Write(stream)
+		server.Close()
+	}()
+	sc := newConn(client, Config{})
+	sc.completed = true
+
+	var serverErr *ServerError
+	if _, err := sc.QueryContext(context.Background(), "SELECT a FROM t1; SELECT b FROM t2", nil); !errors.As(err, &serverErr) {
+		t.Fatalf("QueryContext: server error expected, got %v", err)
+	}
+	if sc.IsValid() {
+		t.Fatalf("QueryContext: connection still valid with responses left")
+	}
+}
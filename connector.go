@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql/driver"
+	"net"
+	"time"
+)
+
+// Connector holds a validated configuration and opens new connections to Siodb.
+// It is meant to be used with sql.OpenDB:
+//
+//	connector, err := siodb.NewConnector(siodb.Config{User: "root", PrivateKey: key})
+//	db := sql.OpenDB(connector)
+type Connector struct {
+	cfg Config // immutable private copy.
+}
+
+// NewConnector returns a Connector for the given configuration. Unset fields
+// take the same defaults as the URI. The private key is loaded from
+// IdentityFile, ~/.ssh/id_rsa by default, only when PrivateKey is nil, and
+// decrypted with IdentityPass if set.
+func NewConnector(cfg Config) (*Connector, error) {
+
+	var err error
+
+	defaults := defaultConfig()
+	if cfg.Protocol == "" {
+		cfg.Protocol = defaults.Protocol
+	}
+	if cfg.Protocol != "siodbs" && cfg.Protocol != "siodb" && cfg.Protocol != "siodbu" {
+		return nil, &DriverError{Message: "Unknown protocol '" + cfg.Protocol + "'."}
+	}
+	if cfg.Host == "" {
+		cfg.Host = defaults.Host
+	}
+	if cfg.Port == "" {
+		cfg.Port = defaults.Port
+	}
+	if cfg.User == "" {
+		cfg.User = defaults.User
+	}
+	if cfg.UnixSocketPath == "" {
+		cfg.UnixSocketPath = defaults.UnixSocketPath
+	}
+
+	if cfg.PrivateKey == nil {
+		if cfg.IdentityFile == "" {
+			cfg.IdentityFile = defaults.IdentityFile
+		}
+		if cfg.PrivateKey, err = loadPrivateKey(cfg.IdentityFile, cfg.IdentityPass); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Connector{
+		cfg: cfg,
+	}, nil
+}
+
+// Connect opens and authenticates a new connection. See https://golang.org/pkg/database/sql/driver/#Connector
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+
+	var err error
+	var dialer net.Dialer
+	var netConn net.Conn
+
+	switch c.cfg.Protocol {
+
+	// Unix socket connection
+	case "siodbu":
+		if netConn, err = dialer.DialContext(ctx, "unix", c.cfg.UnixSocketPath); err != nil {
+			return nil, &DriverError{Message: "Unable to connect to " + c.cfg.UnixSocketPath + "."}
+		}
+
+	// Plain connection
+	case "siodb":
+		if netConn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.cfg.Host, c.cfg.Port)); err != nil {
+			return nil, &DriverError{Message: "Unable to connect to " + c.cfg.Host + "."}
+		}
+
+	// TLS connection
+	case "siodbs":
+		var rawConn net.Conn
+		if rawConn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.cfg.Host, c.cfg.Port)); err != nil {
+			return nil, &DriverError{Message: "Unable to connect to " + c.cfg.Host + "."}
+		}
+		tlsConn := tls.Client(rawConn, &tls.Config{InsecureSkipVerify: true})
+		if deadline, ok := ctx.Deadline(); ok {
+			tlsConn.SetDeadline(deadline)
+		}
+		if err = tlsConn.Handshake(); err != nil {
+			rawConn.Close()
+			return nil, &DriverError{Message: "Unable to connect to " + c.cfg.Host + "."}
+		}
+		tlsConn.SetDeadline(time.Time{})
+		netConn = tlsConn
+	}
+
+	// New siodbConn
+	sc := newConn(netConn, c.cfg)
+	sc.completed = true
+
+	// Authentification, bounded by the context like the dial
+	watcher, err := sc.startWatcher(ctx)
+	if err != nil {
+		sc.netConn.Close()
+		return nil, err
+	}
+	err = watcher.check(sc.authenticate())
+	watcher.stop()
+	if err != nil {
+		sc.netConn.Close()
+		return nil, err
+	}
+
+	return sc, nil
+}
+
+// Driver returns the Siodb driver. See https://golang.org/pkg/database/sql/driver/#Connector
+func (c *Connector) Driver() driver.Driver {
+	return &siodbDriver{}
+}
@@ -0,0 +1,182 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeIdentityFile writes a new RSA private key in PEM format to path,
+// encrypted if password isn't empty.
+func writeIdentityFile(t *testing.T, path string, password string) *rsa.PrivateKey {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	if password != "" {
+		if block, err = x509.EncryptPEMBlock(rand.Reader, block.Type, block.Bytes, []byte(password), x509.PEMCipherAES256); err != nil {
+			t.Fatalf("EncryptPEMBlock: %v", err)
+		}
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err = ioutil.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	return key
+}
+
+func TestNewConnectorDefaults(t *testing.T) {
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	c, err := NewConnector(Config{User: "root", PrivateKey: key})
+	if err != nil {
+		t.Fatalf("NewConnector: %v", err)
+	}
+	defaults := defaultConfig()
+	if c.cfg.Protocol != "siodbs" || c.cfg.Host != defaults.Host || c.cfg.Port != defaults.Port ||
+		c.cfg.UnixSocketPath != defaults.UnixSocketPath || c.cfg.User != "root" || c.cfg.PrivateKey != key {
+		t.Fatalf("NewConnector: unexpected configuration %+v", c.cfg)
+	}
+}
+
+func TestNewConnectorDefaultIdentityFile(t *testing.T) {
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	key := writeIdentityFile(t, filepath.Join(home, ".ssh", "id_rsa"), "")
+
+	c, err := NewConnector(Config{User: "root"})
+	if err != nil {
+		t.Fatalf("NewConnector: %v", err)
+	}
+	if c.cfg.IdentityFile != "~/.ssh/id_rsa" || !key.Equal(c.cfg.PrivateKey) {
+		t.Fatalf("NewConnector: key not loaded from the default identity file")
+	}
+}
+
+func TestNewConnectorEncryptedIdentityFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "id_rsa")
+	key := writeIdentityFile(t, path, "secret")
+
+	c, err := NewConnector(Config{User: "root", IdentityFile: path, IdentityPass: "secret"})
+	if err != nil {
+		t.Fatalf("NewConnector: %v", err)
+	}
+	if !key.Equal(c.cfg.PrivateKey) {
+		t.Fatalf("NewConnector: key not decrypted from the identity file")
+	}
+}
+
+func TestConnectAuthenticationDeadline(t *testing.T) {
+
+	socket := filepath.Join(t.TempDir(), "siodb.socket")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer listener.Close()
+
+	// The server accepts the connection but never answers the session request.
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(ioutil.Discard, conn)
+		}
+	}()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	c, err := NewConnector(Config{Protocol: "siodbu", UnixSocketPath: socket, User: "root", PrivateKey: key})
+	if err != nil {
+		t.Fatalf("NewConnector: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err = c.Connect(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Connect: unexpected error %v", err)
+	}
+}
+
+func TestNewConnectorErrors(t *testing.T) {
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	notPEM := filepath.Join(dir, "not_pem")
+	if err := ioutil.WriteFile(notPEM, []byte("not a key"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	encrypted := filepath.Join(dir, "encrypted")
+	writeIdentityFile(t, encrypted, "secret")
+
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"unknown protocol", Config{Protocol: "http", IdentityFile: notPEM}},
+		{"missing default identity file", Config{}},
+		{"missing identity file", Config{IdentityFile: filepath.Join(dir, "missing")}},
+		{"identity file not PEM encoded", Config{IdentityFile: notPEM}},
+		{"wrong identity file password", Config{IdentityFile: encrypted, IdentityPass: "wrong"}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewConnector(test.cfg); err == nil {
+			t.Fatalf("NewConnector: no error for %s", test.name)
+		}
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+
+	t.Setenv("HOME", "/home/siodb")
+
+	tests := []struct {
+		path     string
+		expanded string
+	}{
+		{"~", "/home/siodb"},
+		{"~/.ssh/id_rsa", "/home/siodb/.ssh/id_rsa"},
+		{"/etc/id_rsa", "/etc/id_rsa"},
+		{"~siodb/id_rsa", "~siodb/id_rsa"},
+		{"keys/~/id_rsa", "keys/~/id_rsa"},
+	}
+
+	for _, test := range tests {
+		expanded, err := expandHome(test.path)
+		if err != nil || expanded != test.expanded {
+			t.Fatalf("expandHome(%q): %q, %v", test.path, expanded, err)
+		}
+	}
+}
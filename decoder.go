@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"io"
+	"sync"
+)
+
+// DecoderFunc reads a non-null value of a column data type from the
+// connection stream. It must read exactly the bytes of the value. attributes
+// describes the attributes of STRUCT values and is nil for other types.
+type DecoderFunc func(r io.Reader, attributes []*AttributeDescription) (driver.Value, error)
+
+// Decoders maps column data types to the decoders used instead of the
+// built-in ones. It is safe for concurrent use, so one registry can be shared
+// by the connections of a Config.
+type Decoders struct {
+	mu       sync.RWMutex
+	decoders map[ColumnDataType]DecoderFunc
+}
+
+// NewDecoders returns an empty registry.
+func NewDecoders() *Decoders {
+	return &Decoders{decoders: make(map[ColumnDataType]DecoderFunc)}
+}
+
+// Register sets the decoder of a column data type, replacing the built-in or
+// previously registered one. A nil decoder restores the built-in decoder.
+func (d *Decoders) Register(columnType ColumnDataType, decoder DecoderFunc) {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if decoder == nil {
+		delete(d.decoders, columnType)
+		return
+	}
+	if d.decoders == nil {
+		d.decoders = make(map[ColumnDataType]DecoderFunc)
+	}
+	d.decoders[columnType] = decoder
+}
+
+// Lookup returns the decoder registered for a column data type.
+func (d *Decoders) Lookup(columnType ColumnDataType) (DecoderFunc, bool) {
+
+	if d == nil {
+		return nil, false
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	decoder, ok := d.decoders[columnType]
+	return decoder, ok
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+)
+
+type instant int64
+
+func TestDecodersOverride(t *testing.T) {
+
+	const customType = ColumnDataType(100)
+
+	decoders := NewDecoders()
+	decoders.Register(ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP, func(r io.Reader, attributes []*AttributeDescription) (driver.Value, error) {
+		buff := make([]byte, 10)
+		if _, err := io.ReadFull(r, buff); err != nil {
+			return nil, err
+		}
+		return instant(binary.LittleEndian.Uint64(buff[2:])), nil
+	})
+	decoders.Register(customType, func(r io.Reader, attributes []*AttributeDescription) (driver.Value, error) {
+		buff := make([]byte, 2)
+		_, err := io.ReadFull(r, buff)
+		return string(buff), err
+	})
+
+	var stream []byte
+	stream = append(stream, 15)
+	stream = append(stream, packDate(2020, time.February, 29, true)...)
+	stream = append(stream, packTime(1, 2, 3, 0)...)
+	stream = append(stream, 'o', 'k')
+	stream = append(stream, 7)
+
+	sc := newRowsConn(stream)
+	sc.cfg.Decoders = decoders
+
+	columns := []*ColumnDescription{
+		{Name: "TS", Type: ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP},
+		{Name: "C", Type: customType},
+		{Name: "I", Type: ColumnDataType_COLUMN_DATA_TYPE_INT8},
+	}
+	dest := make([]driver.Value, len(columns))
+	if err := sc.readRow(dest, columns); err != nil {
+		t.Fatalf("readRow: %v", err)
+	}
+
+	if _, ok := dest[0].(instant); !ok {
+		t.Fatalf("readRow: TS is %T", dest[0])
+	}
+	if dest[1] != "ok" {
+		t.Fatalf("readRow: C is %#v", dest[1])
+	}
+	if dest[2] != int8(7) {
+		t.Fatalf("readRow: I is %#v", dest[2])
+	}
+
+	decoders.Register(ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP, nil)
+	if _, ok := decoders.Lookup(ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP); ok {
+		t.Fatalf("Lookup: the decoder is still registered")
+	}
+}
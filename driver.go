@@ -5,27 +5,32 @@
 package siodb
 
 import (
+	"context"
 	"crypto/rsa"
-	"crypto/tls"
 	"database/sql"
 	"database/sql/driver"
 	"fmt"
-	"net"
 	"net/url"
 	"os/user"
 	"strconv"
+	"time"
 )
 
 // Config holds the connection Configuration
 type Config struct {
-	protocol       string // https://golang.org/pkg/net/#Dial
-	host           string // Network address
-	port           string // Siodb port number
-	user           string // Username
-	identityFile   string // Public key for user
-	privateKey     *rsa.PrivateKey
-	unixSocketPath string // Unix socket path
-	trace          bool   // Trace Siodb protol?
+	Protocol       string          // Connection scheme: siodbs (TLS), siodb (TCP) or siodbu (Unix socket)
+	Host           string          // Network address
+	Port           string          // Siodb port number
+	User           string          // Username
+	IdentityFile   string          // Path to the private key of the user, used if PrivateKey is nil
+	IdentityPass   string          // Password of the identity file, if it is encrypted
+	PrivateKey     *rsa.PrivateKey // Private key of the user
+	UnixSocketPath string          // Unix socket path
+	Trace          bool            // Trace Siodb protol?
+	Loc            *time.Location  // Time zone of naive dates and times, time.Local if nil
+	ValueMode      ValueMode       // Go types of the values returned for the columns
+	Decoders       *Decoders       // Decoders used instead of the built-in ones, if any
+	MaxCommandSize int64           // Maximum size of the text of a command in bytes, 1 GiB if 0, unlimited if negative
 }
 
 type siodbDriver struct{}
@@ -34,60 +39,92 @@ func init() {
 	sql.Register("siodb", &siodbDriver{})
 }
 
-func parseURI(URI string) (cfg Config, err error) {
+func defaultConfig() (cfg Config) {
 
-	// Set default
-	cfg.protocol = "siodbs"
-	cfg.host = "localhost"
-	cfg.port = "50000"
-	cfg.identityFile = "~/.ssh/id_rsa"
-	cfg.trace = false
-	cfg.unixSocketPath = "/run/siodb/siodb.socket"
+	cfg.Protocol = "siodbs"
+	cfg.Host = "localhost"
+	cfg.Port = "50000"
+	cfg.IdentityFile = "~/.ssh/id_rsa"
+	cfg.Trace = false
+	cfg.Loc = time.Local
+	cfg.MaxCommandSize = defaultMaxCommandSize
+	cfg.UnixSocketPath = "/run/siodb/siodb.socket"
 	if usr, err := user.Current(); err == nil {
-		cfg.user = usr.Username
+		cfg.User = usr.Username
 	}
 
+	return cfg
+}
+
+func parseURI(URI string) (cfg Config, err error) {
+
+	// Set default
+	cfg = defaultConfig()
+
 	// Overwrite default with provided URI
 	uri, err := url.Parse(URI)
+	if err != nil {
+		return cfg, &DriverError{Message: "Paring URI: '" + err.Error() + "'."}
+	}
 	if uri.Scheme != "siodbs" && uri.Scheme != "siodb" && uri.Scheme != "siodbu" {
-		return cfg, &siodbDriverError{"Paring URI: unknown scheme '" + uri.Scheme + "'"}
+		return cfg, &DriverError{Message: "Paring URI: unknown scheme '" + uri.Scheme + "'"}
 	}
-	cfg.protocol = uri.Scheme
+	cfg.Protocol = uri.Scheme
 
 	if len(uri.User.Username()) > 0 {
-		cfg.user = uri.User.Username()
+		cfg.User = uri.User.Username()
 	}
 
 	if len(uri.Hostname()) > 0 {
-		cfg.host = uri.Hostname()
+		cfg.Host = uri.Hostname()
 		if len(uri.Port()) > 0 {
-			cfg.port = uri.Port()
+			cfg.Port = uri.Port()
 		}
 	} else {
-		cfg.unixSocketPath, err = url.PathUnescape(uri.EscapedPath())
+		cfg.UnixSocketPath, err = url.PathUnescape(uri.EscapedPath())
 	}
 
 	// Parse Options
 	var options url.Values
 	if options, err = url.ParseQuery(uri.RawQuery); err != nil {
-		return cfg, &siodbDriverError{"Error while paring options from URI: '" + err.Error() + "'."}
+		return cfg, &DriverError{Message: "Error while paring options from URI: '" + err.Error() + "'."}
 	}
 
 	if len(options.Get("identity_file")) > 0 {
-		if cfg.privateKey, err = loadPrivateKey(options.Get("identity_file"), options.Get("identity_file_password")); err != nil {
+		cfg.IdentityFile = options.Get("identity_file")
+		cfg.IdentityPass = options.Get("identity_file_password")
+		if cfg.PrivateKey, err = loadPrivateKey(cfg.IdentityFile, cfg.IdentityPass); err != nil {
 			return cfg, err
 		}
 	}
 
 	if len(options.Get("trace")) > 0 {
 		if trc, err := strconv.ParseBool(options.Get("trace")); err == nil {
-			cfg.trace = trc
+			cfg.Trace = trc
 		} else {
-			return cfg, &siodbDriverError{"Paring URI: option 'trace' can be 'true' or 'false'."}
+			return cfg, &DriverError{Message: "Paring URI: option 'trace' can be 'true' or 'false'."}
 		}
 	}
 
-	if cfg.trace {
+	if len(options.Get("loc")) > 0 {
+		if cfg.Loc, err = time.LoadLocation(options.Get("loc")); err != nil {
+			return cfg, &DriverError{Message: "Paring URI: unknown time zone '" + options.Get("loc") + "' for option 'loc'.", Err: err}
+		}
+	}
+
+	if len(options.Get("value_mode")) > 0 {
+		if cfg.ValueMode, err = ParseValueMode(options.Get("value_mode")); err != nil {
+			return cfg, &DriverError{Message: "Paring URI: option 'value_mode' is a comma separated list of strict, uint64_as_string, timestamp_as_string and binary_as_hex.", Err: err}
+		}
+	}
+
+	if len(options.Get("max_command_size")) > 0 {
+		if cfg.MaxCommandSize, err = strconv.ParseInt(options.Get("max_command_size"), 10, 64); err != nil {
+			return cfg, &DriverError{Message: "Paring URI: option 'max_command_size' must be a number of bytes.", Err: err}
+		}
+	}
+
+	if cfg.Trace {
 		fmt.Printf("## SIODB DRIVER | Config used: %v.\n", cfg)
 	}
 
@@ -96,49 +133,22 @@ func parseURI(URI string) (cfg Config, err error) {
 
 func (d siodbDriver) Open(dsn string) (driver.Conn, error) {
 
-	var cfg Config
-	var err error
-
-	if cfg, err = parseURI(dsn); err != nil {
+	c, err := d.OpenConnector(dsn)
+	if err != nil {
 		return nil, err
 	}
-	c := &connector{
-		cfg: cfg,
-	}
-
-	// New siodbConn
-	sc := &siodbConn{
-		cfg: c.cfg,
-	}
-
-	// Plain connection
-	if sc.cfg.protocol == "siodbu" {
-		if sc.netConn, err = net.Dial("unix", sc.cfg.unixSocketPath); err != nil {
-			return nil, &siodbDriverError{"Unable to connect to " + sc.cfg.unixSocketPath + "."}
-		}
-	}
 
-	// Plain connection
-	if sc.cfg.protocol == "siodb" {
-		if sc.netConn, err = net.Dial("tcp", sc.cfg.host+":"+sc.cfg.port); err != nil {
-			return nil, &siodbDriverError{"Unable to connect to " + sc.cfg.host + "."}
-		}
-	}
-
-	// TLS connection
-	if sc.cfg.protocol == "siodbs" {
-		Config := &tls.Config{InsecureSkipVerify: true}
+	return c.Connect(context.Background())
+}
 
-		if sc.netConn, err = tls.Dial("tcp", sc.cfg.host+":"+sc.cfg.port, Config); err != nil {
-			return nil, &siodbDriverError{"Unable to connect to " + sc.cfg.host + "."}
-		}
-	}
+// OpenConnector parses the URI once and returns a connector reused for every
+// new connection. See https://golang.org/pkg/database/sql/driver/#DriverContext
+func (d siodbDriver) OpenConnector(dsn string) (driver.Connector, error) {
 
-	// Authentification
-	if err := sc.authenticate(); err != nil {
-		return sc, err
+	cfg, err := parseURI(dsn)
+	if err != nil {
+		return nil, err
 	}
 
-	return sc, nil
-
+	return NewConnector(cfg)
 }
@@ -5,22 +5,98 @@
 package siodb
 
 import (
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"strings"
 )
 
-type siodbDriverError struct {
+var (
+	// ErrAuthenticationFailed is returned when Siodb refuses the session or the user credentials.
+	ErrAuthenticationFailed = errors.New("authentication failed")
+	// ErrProtocol is returned when the data received from Siodb doesn't follow the protocol.
+	ErrProtocol = errors.New("protocol error")
+	// ErrUnsupportedType is returned for column data types and parameter types the driver can't handle.
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrCommandTooLarge is returned when the text of a command exceeds Config.MaxCommandSize.
+	ErrCommandTooLarge = errors.New("command too large")
+)
+
+// DriverError is an error raised by the driver itself. Err, when set, is the
+// underlying cause or one of the Err* sentinels, so that errors.Is works.
+type DriverError struct {
 	Message string
+	Err     error
 }
 
-type siodbServerError struct {
-	Number  int32
-	Message string
+// ServerError is an error returned by Siodb. Code and Message come from the
+// first status message; Messages holds every status message of the response.
+type ServerError struct {
+	Code     int32
+	Message  string
+	Messages []*StatusMessage
+}
+
+func (de *DriverError) Error() string {
+	return fmt.Sprintf("Siodb Driver Error: %s", de.Message)
+}
+
+func (de *DriverError) Unwrap() error {
+	return de.Err
+}
+
+func (se *ServerError) Error() string {
+
+	if len(se.Messages) <= 1 {
+		return fmt.Sprintf("Siodb Server Error: %d | %s", se.Code, se.Message)
+	}
+
+	texts := make([]string, len(se.Messages))
+	for idx, msg := range se.Messages {
+		texts[idx] = fmt.Sprintf("%d | %s", msg.GetStatusCode(), msg.GetText())
+	}
+
+	return fmt.Sprintf("Siodb Server Error: %s", strings.Join(texts, "; "))
+}
+
+// HasCode reports whether one of the status messages has the given code.
+func (se *ServerError) HasCode(code int32) bool {
+
+	for _, msg := range se.Messages {
+		if msg.GetStatusCode() == code {
+			return true
+		}
+	}
+
+	return se.Code == code
+}
+
+// StatusCode returns the status code of the first server message carried by err.
+func StatusCode(err error) (code int32, ok bool) {
+
+	var se *ServerError
+	if errors.As(err, &se) {
+		return se.Code, true
+	}
+
+	return 0, false
+}
+
+// badConnError reports a failure that left the connection unusable. It matches
+// driver.ErrBadConn with errors.Is, so that database/sql discards the
+// connection, and unwraps to its cause.
+type badConnError struct {
+	err error
+}
+
+func (bce *badConnError) Error() string {
+	return fmt.Sprintf("Siodb Driver Error: connection discarded: %s", bce.err.Error())
 }
 
-func (sde *siodbDriverError) Error() string {
-	return fmt.Sprintf("Siodb Driver Error: %s", sde.Message)
+func (bce *badConnError) Is(target error) bool {
+	return target == driver.ErrBadConn
 }
 
-func (sse *siodbServerError) Error() string {
-	return fmt.Sprintf("Siodb Server Error: %d | %s", sse.Number, sse.Message)
+func (bce *badConnError) Unwrap() error {
+	return bce.err
 }
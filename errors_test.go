@@ -0,0 +1,52 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerError(t *testing.T) {
+
+	err := checkServerError([]*StatusMessage{
+		{StatusCode: 1, Text: "Statement failed"},
+		{StatusCode: 4, Text: "Table 'T' does not exist"},
+	})
+	wrapped := fmt.Errorf("query: %w", err)
+
+	var se *ServerError
+	if !errors.As(wrapped, &se) || se.Code != 1 || len(se.Messages) != 2 {
+		t.Fatalf("errors.As: unexpected %v", wrapped)
+	}
+	if !strings.Contains(err.Error(), "Statement failed") || !strings.Contains(err.Error(), "Table 'T' does not exist") {
+		t.Fatalf("Error: all messages expected in %q", err.Error())
+	}
+	if code, ok := StatusCode(wrapped); !ok || code != 1 {
+		t.Fatalf("StatusCode: %d, %t", code, ok)
+	}
+	if !se.HasCode(4) || se.HasCode(2) {
+		t.Fatalf("HasCode: wrong status codes")
+	}
+	if _, ok := StatusCode(errors.New("other")); ok {
+		t.Fatalf("StatusCode: status code found in a non server error")
+	}
+}
+
+func TestDriverErrorSentinels(t *testing.T) {
+
+	_, err := formatValue(struct{}{}, time.UTC)
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("formatValue: ErrUnsupportedType expected, got %v", err)
+	}
+
+	var de *DriverError
+	if !errors.As(err, &de) {
+		t.Fatalf("formatValue: DriverError expected, got %T", err)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval is a DATE_INTERVAL or TIME_INTERVAL value. Months and days are kept
+// apart from the sub-day duration because their length in time varies.
+//
+// The driver has no built-in decoder for the interval types and can't bind
+// intervals: Siodb doesn't document their formats. A decoder registered in
+// Config.Decoders can return Interval values.
+type Interval struct {
+	Months   int32
+	Days     int32
+	Duration time.Duration
+}
+
+// ToDuration returns the interval as a time.Duration. It fails if the interval
+// has months or days as the conversion would not be lossless.
+func (i Interval) ToDuration() (time.Duration, error) {
+
+	if i.Months != 0 || i.Days != 0 {
+		return 0, &DriverError{Message: "Interval with months or days can't be converted to a duration."}
+	}
+
+	return i.Duration, nil
+}
+
+// String returns the interval in the ISO 8601 duration format, e.g. P1M2DT3.5S.
+func (i Interval) String() string {
+
+	var b strings.Builder
+	b.WriteString("P")
+	if i.Months != 0 {
+		b.WriteString(strconv.FormatInt(int64(i.Months), 10) + "M")
+	}
+	if i.Days != 0 {
+		b.WriteString(strconv.FormatInt(int64(i.Days), 10) + "D")
+	}
+	if i.Duration != 0 || (i.Months == 0 && i.Days == 0) {
+		b.WriteString("T" + formatSeconds(i.Duration) + "S")
+	}
+
+	return b.String()
+}
+
+// formatSeconds formats d in seconds without losing precision: the seconds
+// and the fraction are formatted apart.
+func formatSeconds(d time.Duration) string {
+
+	sign := ""
+	abs := uint64(d)
+	if d < 0 {
+		sign = "-"
+		abs = -abs
+	}
+
+	s := sign + strconv.FormatUint(abs/uint64(time.Second), 10)
+	if nanos := abs % uint64(time.Second); nanos != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+	}
+
+	return s
+}
+
+// parseDuration parses a decimal number of units without losing precision,
+// up to the nanosecond. The unit is a whole number of seconds.
+func parseDuration(number string, unit time.Duration) (time.Duration, bool) {
+
+	integer, fraction := number, ""
+	if dot := strings.IndexByte(number, '.'); dot >= 0 {
+		integer, fraction = number[:dot], number[dot+1:]
+	}
+	negative := strings.HasPrefix(integer, "-")
+	if integer == "" || integer == "-" || integer == "+" || len(fraction) > 9 || (fraction == "" && strings.Contains(number, ".")) {
+		return 0, false
+	}
+
+	whole, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil || whole > math.MaxInt64/int64(unit) || whole < math.MinInt64/int64(unit) {
+		return 0, false
+	}
+
+	// Nine digits of a fraction of a whole number of seconds are whole nanoseconds.
+	var nanos int64
+	if fraction != "" {
+		if nanos, err = strconv.ParseInt(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64); err != nil || nanos < 0 || fraction[0] == '+' || fraction[0] == '-' {
+			return 0, false
+		}
+		nanos *= int64(unit / time.Second)
+	}
+	if negative {
+		nanos = -nanos
+	}
+
+	d := time.Duration(whole)*unit + time.Duration(nanos)
+	if (negative && d > 0) || (!negative && d < 0) {
+		return 0, false
+	}
+
+	return d, true
+}
+
+// Value implements the driver.Valuer interface. Intervals can't be bound yet:
+// nothing documents the interval literals accepted by Siodb.
+func (i Interval) Value() (driver.Value, error) {
+	return nil, &DriverError{Message: "Interval values can't be bound: the Siodb interval literal format is unknown.", Err: ErrUnsupportedType}
+}
+
+// Scan implements the sql.Scanner interface.
+func (i *Interval) Scan(src interface{}) error {
+
+	var err error
+
+	switch v := src.(type) {
+	case Interval:
+		*i = v
+	case nil:
+		*i = Interval{}
+	case string:
+		*i, err = ParseInterval(v)
+	case []byte:
+		*i, err = ParseInterval(string(v))
+	default:
+		err = &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T into an Interval.", src), Err: ErrUnsupportedType}
+	}
+
+	return err
+}
+
+// ParseInterval parses an ISO 8601 duration such as P1Y2M3DT4H5M6.7S.
+// Years are counted as 12 months and weeks as 7 days.
+func ParseInterval(s string) (Interval, error) {
+
+	var i Interval
+	invalid := &DriverError{Message: "Invalid interval '" + s + "'."}
+
+	rest := s
+	if !strings.HasPrefix(rest, "P") || len(rest) == 1 {
+		return i, invalid
+	}
+	rest = rest[1:]
+
+	inTime := false
+	timeComponents := 0
+	for len(rest) > 0 {
+		if rest[0] == 'T' {
+			if inTime {
+				return i, invalid
+			}
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+
+		end := strings.IndexAny(rest, "YMWDHS")
+		if end <= 0 {
+			return i, invalid
+		}
+		number, unit := rest[:end], rest[end]
+		rest = rest[end+1:]
+
+		if inTime {
+			timeComponents++
+			var d time.Duration
+			var ok bool
+			switch unit {
+			case 'H':
+				d, ok = parseDuration(number, time.Hour)
+			case 'M':
+				d, ok = parseDuration(number, time.Minute)
+			case 'S':
+				d, ok = parseDuration(number, time.Second)
+			}
+			sum := i.Duration + d
+			if !ok || (d > 0 && sum < i.Duration) || (d < 0 && sum > i.Duration) {
+				return i, invalid
+			}
+			i.Duration = sum
+			continue
+		}
+
+		n, err := strconv.ParseInt(number, 10, 32)
+		if err != nil {
+			return i, invalid
+		}
+		switch unit {
+		case 'Y':
+			i.Months += int32(n) * 12
+		case 'M':
+			i.Months += int32(n)
+		case 'W':
+			i.Days += int32(n) * 7
+		case 'D':
+			i.Days += int32(n)
+		default:
+			return i, invalid
+		}
+	}
+
+	// The time designator must be followed by a component: "PT" and "P1DT" are invalid.
+	if inTime && timeComponents == 0 {
+		return i, invalid
+	}
+
+	return i, nil
+}
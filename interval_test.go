@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestReadIntervalUnsupported(t *testing.T) {
+
+	for _, columnType := range []ColumnDataType{
+		ColumnDataType_COLUMN_DATA_TYPE_DATE_INTERVAL,
+		ColumnDataType_COLUMN_DATA_TYPE_TIME_INTERVAL,
+	} {
+		sc := newStreamConn(make([]byte, 16))
+		if _, err := sc.readFieldData(columnType); !errors.Is(err, ErrUnsupportedType) {
+			t.Fatalf("readFieldData(%s): ErrUnsupportedType expected, got %v", columnType, err)
+		}
+	}
+
+	if _, err := (Interval{Days: 1}).Value(); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("Value: ErrUnsupportedType expected, got %v", err)
+	}
+}
+
+func TestIntervalToDuration(t *testing.T) {
+
+	if _, err := (Interval{Months: 14, Days: -3}).ToDuration(); err == nil {
+		t.Fatalf("ToDuration: error expected for a date interval")
+	}
+	if d, err := (Interval{Duration: 90 * time.Minute}).ToDuration(); err != nil || d != 90*time.Minute {
+		t.Fatalf("ToDuration: %v, %v", d, err)
+	}
+}
+
+func TestIntervalRoundTrip(t *testing.T) {
+
+	intervals := []Interval{
+		{},
+		{Months: 14},
+		{Months: -1, Days: 2, Duration: 3*time.Hour + 500*time.Millisecond},
+		{Duration: -time.Second},
+		{Duration: time.Duration(math.MaxInt64)},
+		{Duration: time.Duration(math.MinInt64)},
+		{Days: 1, Duration: 100000*time.Hour + time.Nanosecond},
+		{Duration: -1500 * time.Millisecond},
+	}
+
+	for _, interval := range intervals {
+		value := interval.String()
+		var scanned Interval
+		if err := scanned.Scan(value); err != nil {
+			t.Fatalf("Scan(%v): %v", value, err)
+		}
+		if scanned != interval {
+			t.Fatalf("Scan(%v): %v != %v", value, scanned, interval)
+		}
+	}
+
+	parsed := []struct {
+		s        string
+		interval Interval
+	}{
+		{"P1Y2W", Interval{Months: 12, Days: 14}},
+		{"PT0.000000001H", Interval{Duration: 3600 * time.Nanosecond}},
+		{"PT1.1H", Interval{Duration: time.Hour + 6*time.Minute}},
+		{"PT2562047H47M16.854775807S", Interval{Duration: time.Duration(math.MaxInt64)}},
+		{"PT-0.5M", Interval{Duration: -30 * time.Second}},
+	}
+	for _, test := range parsed {
+		if i, err := ParseInterval(test.s); err != nil || i != test.interval {
+			t.Fatalf("ParseInterval(%q): %v, %v", test.s, i, err)
+		}
+	}
+	if s := (Interval{Duration: time.Duration(math.MaxInt64)}).String(); s != "PT9223372036.854775807S" {
+		t.Fatalf("String: %s", s)
+	}
+	for _, s := range []string{"P1H", "PT", "P1DT", "PT1.S", "PT.5S", "PT1.0000000001S", "PT9223372037S", "PT1.-5S", "PT2562048H", "PT2562047H48M", "PT1.5D", "PT1.0000000001M"} {
+		if _, err := ParseInterval(s); err == nil {
+			t.Fatalf("ParseInterval(%q): error expected", s)
+		}
+	}
+}
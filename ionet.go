@@ -7,16 +7,17 @@ package siodb
 import (
 	"database/sql/driver"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"time"
-	"unicode/utf8"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/siodb/siodb-go-driver/codec"
 )
 
 func (sc *siodbConn) debug(message string, args ...interface{}) {
-	if sc.cfg.trace {
+	if sc.cfg.Trace {
 		fmt.Printf("## SIODB DRIVER | "+message+"\n", args...)
 	}
 }
@@ -29,8 +30,9 @@ func (sc *siodbConn) cleanupBuffer() (cpt int16, err error) {
 
 	for {
 		// Get Current Row Size
-		if _, rowLength, err = sc.readVarint(); err != nil {
-			return 0, &siodbDriverError{"Unable to read the row size."}
+		if rowLength, err = sc.readVarint(); err != nil {
+			sc.bad = true
+			return 0, &DriverError{Message: "Unable to read the row size."}
 		}
 		if rowLength == 0 {
 			sc.debug("cleanupBuffer | Dropped %d rows so far.", cpt)
@@ -38,40 +40,61 @@ func (sc *siodbConn) cleanupBuffer() (cpt int16, err error) {
 			return cpt, err
 		}
 		sc.debug("cleanupBuffer | Row size detected: %d.", rowLength)
-		buff := make([]byte, rowLength)
-		_, err = io.ReadFull(sc.netConn, buff)
+		if _, err = sc.r.Discard(int(rowLength)); err != nil {
+			sc.bad = true
+			return cpt, &DriverError{Message: "Unable to drop the row data."}
+		}
 
 		cpt++
 	}
 
 }
-func (sc *siodbConn) writeServerCommand(sqlText string) error {
 
-	sc.RequestID = 1
+// moreResponses reports whether responses to the last command are still to be read.
+func (sc *siodbConn) moreResponses() bool {
+	return sc.responseID+1 < sc.responseCount
+}
 
-	command := &Command{
-		RequestID: sc.RequestID,
-		Text:      sqlText,
-	}
+// discardResponses drops the rows left in the current dataset and reads
+// through the remaining responses to the last command. It returns the first
+// server error found in the discarded responses.
+func (sc *siodbConn) discardResponses() (serverErr error, err error) {
 
-	if len(command.Text) < 300 {
-		sc.debug("writeServerCommand | Message to send: %v", command)
-	} else {
-		sc.debug("writeServerCommand | Message too big to dump.")
-	}
+	for {
+		if !sc.completed {
+			if _, err = sc.cleanupBuffer(); err != nil {
+				return serverErr, err
+			}
+		}
 
-	var buf [binary.MaxVarintLen32]byte
-	encodedLength := binary.PutUvarint(buf[:], uint64(1))
-	sc.netConn.Write(buf[:encodedLength])
-	writeMessage(sc.netConn, command)
+		if !sc.moreResponses() {
+			return serverErr, nil
+		}
+
+		var sr ServerResponse
+		if sr, err = sc.readServer(); err != nil {
+			return serverErr, err
+		}
+		if serverErr == nil {
+			serverErr = checkServerError(sr.Message)
+		}
+	}
+}
 
-	return nil
+// writeServerCommand sends the command. A failure is returned as
+// driver.ErrBadConn: the server can't execute a command it hasn't fully received.
+func (sc *siodbConn) writeServerCommand(sqlText string) error {
+	return sc.writeCommand(&commandText{chunks: []string{sqlText}})
 }
 
+// readServer reads the response to the last command. A failure leaves the
+// stream in an unknown state: the connection is marked as bad but the error is
+// not driver.ErrBadConn as the server may have executed the command already.
 func (sc *siodbConn) readServer() (serverResponse ServerResponse, err error) {
 
 	// Get Message
 	if _, err = sc.ReadMessage(2, &serverResponse); err != nil {
+		sc.bad = true
 		return serverResponse, err
 	}
 
@@ -80,10 +103,22 @@ func (sc *siodbConn) readServer() (serverResponse ServerResponse, err error) {
 	// Check request ID
 	sc.debug("readServer | Request Id: %d.", serverResponse.RequestID)
 	if serverResponse.RequestID != sc.RequestID {
-		return serverResponse, &siodbDriverError{"Wrong request ID in the server response."}
+		sc.bad = true
+		return serverResponse, &DriverError{Message: "Wrong request ID in the server response.", Err: ErrProtocol}
+	}
+
+	// Track position in the series of responses, the count is sent in the first one only.
+	sc.responseID = serverResponse.ResponseId
+	if serverResponse.ResponseId == 0 {
+		sc.responseCount = serverResponse.ResponseCount
+		if sc.responseCount == 0 {
+			sc.responseCount = 1
+		}
 	}
+	sc.debug("readServer | Response %d of %d.", sc.responseID+1, sc.responseCount)
 
 	// Check dataset presence
+	sc.nullAllowed = false
 	var columnCount int = len(serverResponse.ColumnDescription)
 	if columnCount == 0 {
 
@@ -106,11 +141,7 @@ func (sc *siodbConn) readServer() (serverResponse ServerResponse, err error) {
 
 		// Derive null Bitmask size if one column can be null.
 		if sc.nullAllowed == true {
-			if columnCount%8 == 0 {
-				sc.nullBitmaskByteSize = columnCount / 8
-			} else {
-				sc.nullBitmaskByteSize = columnCount/8 + 1
-			}
+			sc.nullBitmaskByteSize = codec.NullBitmaskSize(columnCount)
 			sc.debug("readRow | Null Bitmask size in bytes: %d.", sc.nullBitmaskByteSize)
 		}
 
@@ -124,9 +155,15 @@ func (sc *siodbConn) readRow(dest []driver.Value, columnDesc []*ColumnDescriptio
 	var rowLength uint64
 	var err error
 
+	// No row stream follows a response without dataset.
+	if sc.completed {
+		return io.EOF
+	}
+
 	// Get Current Row Size
-	if _, rowLength, err = sc.readVarint(); err != nil {
-		return &siodbDriverError{"Unable to read the row size."}
+	if rowLength, err = sc.readVarint(); err != nil {
+		sc.bad = true
+		return &DriverError{Message: "Unable to read the row size."}
 	}
 
 	sc.debug("readRow | --------------------------------------------------")
@@ -139,30 +176,29 @@ func (sc *siodbConn) readRow(dest []driver.Value, columnDesc []*ColumnDescriptio
 	}
 
 	// Read null Bitmask to figure out null value which are not streamed.
-	var Bitmask []byte
+	var Bitmask codec.NullBitmask
 	if sc.nullAllowed == true {
-		Bitmask = make([]byte, sc.nullBitmaskByteSize)
-		if _, err = io.ReadFull(sc.netConn, Bitmask); err != nil {
-			return &siodbDriverError{"Fail to read the bitmask byte(s)."}
+		if Bitmask, err = codec.ReadNullBitmask(sc.r, len(columnDesc)); err != nil {
+			sc.bad = true
+			return &DriverError{Message: "Fail to read the bitmask byte(s)."}
 		}
 		sc.debug("readRow | Bitmask value : %08b.", Bitmask)
 	}
 
 	// Read Row data
+	var mapErr error
 	for idx, column := range columnDesc {
 
-		// Get Bit value for current column
-		var IsNull byte
-		if sc.nullAllowed == true {
-			mask := byte(1 << (idx % 8))
-			sc.debug("readRow | Bit %d with mask value: %08b", (idx % 8), mask)
-			IsNull = (Bitmask[idx/8] & mask) >> (idx % 8)
-			sc.debug("readRow | Bitmask in pos: %d. Is this cell null? => %d", Bitmask[idx/8], IsNull)
-		}
-
-		if IsNull == byte(0) { // If not null
-			if dest[idx], err = sc.readFieldData(column.Type); err != nil {
-				return &siodbDriverError{"Fail to read field " + column.Name + " from current row | " + err.Error()}
+		if !Bitmask.IsNull(idx) { // If not null
+			if dest[idx], err = sc.readValue(column.Type, column.Attribute); err != nil {
+				sc.bad = true
+				return &DriverError{Message: "Fail to read field " + column.Name + " from current row | " + err.Error(), Err: err}
+			}
+			// Go on reading the row on mapping errors to stay in sync.
+			if mapErr == nil {
+				if dest[idx], err = sc.cfg.ValueMode.mapValue(column.Type, column.Attribute, dest[idx]); err != nil {
+					mapErr = &DriverError{Message: "Fail to map field " + column.Name + " from current row | " + err.Error(), Err: err}
+				}
 			}
 		} else { // if null
 			dest[idx] = nil
@@ -171,386 +207,174 @@ func (sc *siodbConn) readRow(dest []driver.Value, columnDesc []*ColumnDescriptio
 
 	}
 
-	return nil
+	return mapErr
 }
 
-func (sc *siodbConn) readFieldData(ColumnType ColumnDataType) (dest driver.Value, err error) {
-
-	sc.debug("readFieldData | Type detected: %s.", ColumnType)
-	var bytesRead int
-
-	switch ColumnType {
-
-	case ColumnDataType_COLUMN_DATA_TYPE_BOOL:
-
-		var Value bool
-		err := binary.Read(sc.netConn, binary.LittleEndian, &Value)
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %t.", bytesRead, err, Value)
-		return Value, err
-
-	case ColumnDataType_COLUMN_DATA_TYPE_INT8:
-
-		var Value int8
-		err := binary.Read(sc.netConn, binary.LittleEndian, &Value)
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, Value)
-		return Value, err
+// readValue reads a non-null value with the decoder registered for its type,
+// or else the built-in one. Structured values are decoded according to their
+// attribute descriptions.
+func (sc *siodbConn) readValue(ColumnType ColumnDataType, attributes []*AttributeDescription) (driver.Value, error) {
 
-	case ColumnDataType_COLUMN_DATA_TYPE_UINT8:
-
-		var Value uint8
-		err := binary.Read(sc.netConn, binary.LittleEndian, &Value)
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, Value)
-		return Value, err
-
-	case ColumnDataType_COLUMN_DATA_TYPE_INT16:
-
-		var Value int16
-		err := binary.Read(sc.netConn, binary.LittleEndian, &Value)
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, Value)
-		return Value, err
-
-	case ColumnDataType_COLUMN_DATA_TYPE_UINT16:
-
-		var Value uint16
-		err := binary.Read(sc.netConn, binary.LittleEndian, &Value)
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, Value)
-		return Value, err
-
-	case ColumnDataType_COLUMN_DATA_TYPE_INT32:
-
-		bytesRead, Value, err := sc.readVarint()
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, int32(Value))
-		return int32(Value), err
+	if decoder, ok := sc.cfg.Decoders.Lookup(ColumnType); ok {
+		sc.debug("readValue | Registered decoder for type %s.", ColumnType)
+		return decoder(sc.r, attributes)
+	}
 
-	case ColumnDataType_COLUMN_DATA_TYPE_UINT32:
+	if ColumnType == ColumnDataType_COLUMN_DATA_TYPE_STRUCT {
+		return sc.readStruct(attributes)
+	}
 
-		bytesRead, Value, err := sc.readVarint()
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, uint32(Value))
-		return uint32(Value), err
+	return sc.readFieldData(ColumnType)
+}
 
-	case ColumnDataType_COLUMN_DATA_TYPE_INT64:
+// readStruct reads a structured value: a null bitmask when one attribute can
+// be null, followed by the non-null attribute values in order.
+func (sc *siodbConn) readStruct(attributes []*AttributeDescription) (Struct, error) {
 
-		bytesRead, Value, err := sc.readVarint()
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, int64(Value))
-		return int64(Value), err
+	var err error
 
-	case ColumnDataType_COLUMN_DATA_TYPE_UINT64:
+	sc.debug("readStruct | Number of attributes: %d.", len(attributes))
 
-		bytesRead, Value, err := sc.readVarint()
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, uint64(Value))
-		return uint64(Value), err
+	var Bitmask codec.NullBitmask
+	for _, attribute := range attributes {
+		if attribute.IsNull {
+			if Bitmask, err = codec.ReadNullBitmask(sc.r, len(attributes)); err != nil {
+				return Struct{}, err
+			}
+			sc.debug("readStruct | Bitmask value : %08b.", Bitmask)
+			break
+		}
+	}
 
-	case ColumnDataType_COLUMN_DATA_TYPE_FLOAT:
+	value := Struct{Fields: make([]StructField, len(attributes))}
+	for idx, attribute := range attributes {
+		value.Fields[idx].Name = attribute.Name
+		if Bitmask.IsNull(idx) {
+			sc.debug("readStruct | NULL Value for %s.", attribute.Name)
+			continue
+		}
+		if value.Fields[idx].Value, err = sc.readValue(attribute.Type, attribute.Attribute); err != nil {
+			return Struct{}, &DriverError{Message: "Fail to read attribute " + attribute.Name + " | " + err.Error(), Err: err}
+		}
+	}
 
-		var Value float32
-		err := binary.Read(sc.netConn, binary.LittleEndian, &Value)
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, float32(Value))
-		return float32(Value), err
+	return value, nil
+}
 
-	case ColumnDataType_COLUMN_DATA_TYPE_DOUBLE:
+// readFieldData reads a non-null value of a column data type with the codec
+// package and returns it in the form of the driver.
+func (sc *siodbConn) readFieldData(ColumnType ColumnDataType) (dest driver.Value, err error) {
 
-		var Value float64
-		err := binary.Read(sc.netConn, binary.LittleEndian, &Value)
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %d.", bytesRead, err, float64(Value))
-		return float64(Value), err
+	sc.debug("readFieldData | Type detected: %s.", ColumnType)
 
-	case ColumnDataType_COLUMN_DATA_TYPE_TEXT:
+	switch ColumnType {
+	case ColumnDataType_COLUMN_DATA_TYPE_STRUCT:
+		// Structured values are read by readValue, which has the attribute descriptions.
+		return nil, &DriverError{Message: "Data type '" + ColumnType.String() + "' can't be read without attribute descriptions.", Err: ErrUnsupportedType}
+	case ColumnDataType_COLUMN_DATA_TYPE_MAX, ColumnDataType_COLUMN_DATA_TYPE_UNKNOWN:
+		return nil, &DriverError{Message: "Data type '" + ColumnType.String() + "' not supported yet.", Err: ErrUnsupportedType}
+	}
 
-		bytesRead, TextLength, err := sc.readVarint()
-		sc.debug("readFieldData   | bytesRead: %d | err: %d | TextLength: %d.", bytesRead, err, TextLength)
-		var Value string
-		buff := make([]byte, TextLength)
-		bytesRead, err = io.ReadFull(sc.netConn, buff)
-		for len(buff) > 0 {
-			r, size := utf8.DecodeRune(buff)
-			Value = Value + fmt.Sprintf("%c", r)
+	value, err := codec.Decode(sc.r, codec.DataType(ColumnType), sc.location())
+	if err != nil {
+		switch {
+		case errors.Is(err, codec.ErrUnsupportedType):
+			return nil, &DriverError{Message: "Data type '" + ColumnType.String() + "' not supported yet.", Err: ErrUnsupportedType}
+		case errors.Is(err, codec.ErrInvalidData):
+			return nil, &DriverError{Message: err.Error(), Err: ErrProtocol}
+		}
+		return nil, err
+	}
 
-			buff = buff[size:]
+	switch v := value.(type) {
+	case []byte:
+		if ColumnType == ColumnDataType_COLUMN_DATA_TYPE_BINARY {
+			sc.debug("readFieldData   |--> Size: %d | Value [disabled for BLOB]", len(v))
+			return v, nil
 		}
-		sc.debug("readFieldData   |--> bytesRead: %d | err: %d | Value: %s.", bytesRead, err, Value)
-		return Value, err
-
-	case ColumnDataType_COLUMN_DATA_TYPE_NTEXT:
-
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-
-	case ColumnDataType_COLUMN_DATA_TYPE_BINARY:
-
-		bytesRead, blobLength, err := sc.readVarint()
-		sc.debug("readFieldData   | bytesRead: %d | err: %d | blobLength: %d.", bytesRead, err, blobLength)
-		Value := make([]byte, blobLength)
-		bytesRead, err = io.ReadFull(sc.netConn, Value)
-		sc.debug(" |--> Size: %v | Value [disabled for BLOB]", bytesRead)
-		return Value, err
-
-	case ColumnDataType_COLUMN_DATA_TYPE_DATE:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_TIME:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_TIME_WITH_TZ:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-
-	case ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP:
-
-		var Value time.Time
-
-		// Get date part, 4 first bytes
-		buff := make([]byte, 4)
-		_, err = io.ReadFull(sc.netConn, buff)
-		hasTimePart := int(buff[0] & byte(1))
-		sc.debug(" |--> hasTimePart     : %t", hasTimePart)
-		dayOfWeek := int(buff[0] & byte(14) >> 1)
-		sc.debug(" |--> dayOfWeek       : %d", dayOfWeek)
-		dayOfMonth := int(((buff[0] & byte(240) >> 4) + (buff[1] & byte(1) << 4)) + 1)
-		sc.debug(" |--> dayOfMonth      : %d", dayOfMonth)
-		month := int((buff[1] & byte(30) >> 1) + 1)
-		sc.debug(" |--> month           : %d", month)
-		sliceYear := []byte{
-			byte(0),
-			byte((buff[3] & byte(224) >> 5)),
-			byte((buff[2] & byte(224) >> 5) + (buff[3] & byte(31) << 3)),
-			byte((buff[1] & byte(224) >> 5) + (buff[2] & byte(31) << 3)),
+	case string:
+		if ColumnType == ColumnDataType_COLUMN_DATA_TYPE_XML {
+			value = XML(v)
 		}
-		year := int(binary.BigEndian.Uint32(sliceYear[:]))
-		sc.debug(" |--> year            : %d", int(year))
-
-		// Get time part if any, 6 next bytes
-		if hasTimePart == 1 {
-			buff := make([]byte, 6)
-			_, err = io.ReadFull(sc.netConn, buff)
-
-			reserved1 := buff[0] & byte(1)
-			sc.debug(" |--> reserved1       : %d", reserved1)
-
-			sliceNanos := []byte{
-				byte((buff[3] & byte(126) >> 1)),
-				byte((buff[2] & byte(254) >> 1) + (buff[3] & byte(1) << 7)),
-				byte((buff[1] & byte(254) >> 1) + (buff[2] & byte(1) << 7)),
-				byte((buff[0] & byte(254) >> 1) + (buff[1] & byte(1) << 7)),
-			}
-			nanos := int(binary.BigEndian.Uint32(sliceNanos[:]))
-			sc.debug(" |--> nanos           : %d", nanos)
-
-			seconds := int((buff[3] & byte(128) >> 7) + (buff[4] & byte(31) << 1))
-			sc.debug(" |--> seconds         : %d", seconds)
+	case [16]byte:
+		value = UUID(v).String()
+	}
+	sc.debug("readFieldData   |--> Value: %v.", value)
 
-			minutes := int((buff[4] & byte(224) >> 5) + (buff[5] & byte(7) << 3))
-			sc.debug(" |--> minutes         : %d", minutes)
+	return value, nil
+}
 
-			hours := int((buff[5] & byte(248) >> 3))
-			sc.debug(" |--> hours           : %d", hours)
+// location returns the time zone in which naive dates and times are interpreted.
+func (sc *siodbConn) location() *time.Location {
 
-			Value = time.Date(year, time.Month(month), dayOfMonth, hours, minutes, seconds, nanos, time.Local)
+	if sc.cfg.Loc == nil {
+		return time.Local
+	}
 
-		} else {
+	return sc.cfg.Loc
+}
 
-			Value = time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, time.Local)
+// readVarint reads a varint from the buffered stream.
+func (sc *siodbConn) readVarint() (uint64, error) {
 
-		}
+	return binary.ReadUvarint(sc.r)
+}
 
-		return Value, err
-
-	case ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP_WITH_TZ:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_DATE_INTERVAL:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_TIME_INTERVAL:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_STRUCT:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_XML:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_JSON:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_UUID:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_MAX:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	case ColumnDataType_COLUMN_DATA_TYPE_UNKNOWN:
-		// TODO: implement type
-		sc.debug("Data type '%q' is not supported yet.", ColumnType)
-		return nil, &siodbDriverError{"Data type '" + ColumnType.String() + "' not supported yet."}
-	default:
-
-		sc.debug("Data type '%q' unknown.", ColumnType)
-		return nil, &siodbDriverError{"Unknown data type."}
+// appendMessage appends a message framed as sent on the wire: its type id,
+// its length and the protobuf message.
+func appendMessage(buff []byte, messageTypeID uint64, m proto.Message) ([]byte, error) {
 
+	em, err := proto.Marshal(m)
+	if err != nil {
+		return buff, err
 	}
-}
 
-func (sc *siodbConn) readVarint() (bytesRead int, n uint64, err error) {
-
-	// Function readVarint()
-	// source: https://github.com/stashed/stash/blob/master/vendor/github.com/matttproud/golang_protobuf_extensions/pbutil/decode.go
-	//
-	// Copyright 2013 Matt T. Proud
-	//
-	// Licensed under the Apache License, Version 2.0 (the "License");
-	// you may not use this file except in compliance with the License.
-	// You may obtain a copy of the License at
-	//
-	//     http://www.apache.org/licenses/LICENSE-2.0
-	//
-	// Unless required by applicable law or agreed to in writing, software
-	// distributed under the License is distributed on an "AS IS" BASIS,
-	// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-	// See the License for the specific language governing permissions and
-	// limitations under the License.
-
-	var prefixBuf [binary.MaxVarintLen64]byte
-	var varIntBytes int
-	for varIntBytes == 0 { // i.e. no varint has been decoded yet.
-		if bytesRead >= len(prefixBuf) {
-			return bytesRead, n, fmt.Errorf("invalid varint64 encountered")
-		}
-		// We have to read byte by byte here to avoid reading more bytes
-		// than required. Each read byte is appended to what we have
-		// read before.
-		newBytesRead, err := sc.netConn.Read(prefixBuf[bytesRead : bytesRead+1])
-		if newBytesRead == 0 {
-			if io.EOF == err {
-				return bytesRead, n, nil
-			} else if err != nil {
-				return bytesRead, n, err
-			}
-			// A Reader should not return (0, nil), but if it does,
-			// it should be treated as no-op (according to the
-			// Reader contract). So let's go on...
-			continue
-		}
-		bytesRead += newBytesRead
-		// Now present everything read so far to the varint decoder and
-		// see if a varint can be decoded already.
-		n, varIntBytes = proto.DecodeVarint(prefixBuf[:bytesRead])
-	}
+	buff = appendUvarint(buff, messageTypeID)
+	buff = appendUvarint(buff, uint64(len(em)))
 
-	return bytesRead, n, err
+	return append(buff, em...), nil
 }
 
-func writeMessage(w io.Writer, m proto.Message) (int, error) {
-
-	// Function ReadMessage()
-	// source: https://github.com/stashed/stash/blob/master/vendor/github.com/matttproud/golang_protobuf_extensions/pbutil/encode.go
-	//
-	// Copyright 2013 Matt T. Proud
-	//
-	// Licensed under the Apache License, Version 2.0 (the "License");
-	// you may not use this file except in compliance with the License.
-	// You may obtain a copy of the License at
-	//
-	//     http://www.apache.org/licenses/LICENSE-2.0
-	//
-	// Unless required by applicable law or agreed to in writing, software
-	// distributed under the License is distributed on an "AS IS" BASIS,
-	// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-	// See the License for the specific language governing permissions and
-	// limitations under the License.
+// writeMessage sends a message in a single flush.
+func (sc *siodbConn) writeMessage(messageTypeID uint64, m proto.Message) error {
 
-	em, err := proto.Marshal(m)
-	if nil != err {
-		return 0, err
+	buff, err := appendMessage(nil, messageTypeID, m)
+	if err != nil {
+		return err
 	}
 
-	var buf [binary.MaxVarintLen32]byte
-	encodedLength := binary.PutUvarint(buf[:], uint64(proto.Size(m)))
-
-	vib, err := w.Write(buf[:encodedLength])
-	if nil != err {
-		return vib, err
+	if _, err = sc.w.Write(buff); err != nil {
+		return err
 	}
 
-	pbmmb, err := w.Write(em)
-
-	return vib + pbmmb, err
+	return sc.w.Flush()
 }
 
+// ReadMessage reads a message of the expected type. It returns the size of
+// the protobuf message.
 func (sc *siodbConn) ReadMessage(messageTypeID uint64, m proto.Message) (n int, err error) {
 
-	// Function ReadMessage()
-	// source: https://github.com/stashed/stash/blob/master/vendor/github.com/matttproud/golang_protobuf_extensions/pbutil/decode.go
-	//
-	// Copyright 2013 Matt T. Proud
-	//
-	// Licensed under the Apache License, Version 2.0 (the "License");
-	// you may not use this file except in compliance with the License.
-	// You may obtain a copy of the License at
-	//
-	//     http://www.apache.org/licenses/LICENSE-2.0
-	//
-	// Unless required by applicable law or agreed to in writing, software
-	// distributed under the License is distributed on an "AS IS" BASIS,
-	// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-	// See the License for the specific language governing permissions and
-	// limitations under the License.
-
-	var prefixBuf [binary.MaxVarintLen64]byte
-	var bytesRead, varIntBytes int
-	var messageLength uint64
-	var readMessageTypeID uint64
+	var readMessageTypeID, messageLength uint64
 
 	// Read and check Message Type Id
-	_, readMessageTypeID, err = sc.readVarint()
+	if readMessageTypeID, err = sc.readVarint(); err != nil {
+		return 0, err
+	}
 	if messageTypeID != readMessageTypeID {
-		return 0, &siodbDriverError{"Wrong message type id."}
+		return 0, &DriverError{Message: "Wrong message type id.", Err: ErrProtocol}
 	}
 	sc.debug("readServerMessage | Message Type Id: %d.", readMessageTypeID)
 
 	// Read Message
-	for varIntBytes == 0 { // i.e. no varint has been decoded yet.
-		if bytesRead >= len(prefixBuf) {
-			return 0, fmt.Errorf("invalid varint64 encountered")
-		}
-		// We have to read byte by byte here to avoid reading more bytes
-		// than required. Each read byte is appended to what we have
-		// read before.
-		newBytesRead, err := sc.netConn.Read(prefixBuf[bytesRead : bytesRead+1])
-		if newBytesRead == 0 {
-			if io.EOF == err {
-				return 0, nil
-			} else if err != nil {
-				return 0, err
-			}
-			// A Reader should not return (0, nil), but if it does,
-			// it should be treated as no-op (according to the
-			// Reader contract). So let's go on...
-			continue
-		}
-		bytesRead += newBytesRead
-		// Now present everything read so far to the varint decoder and
-		// see if a varint can be decoded already.
-		messageLength, varIntBytes = proto.DecodeVarint(prefixBuf[:bytesRead])
+	if messageLength, err = sc.readVarint(); err != nil {
+		return 0, err
 	}
-
 	sc.debug("readServerMessage | %d.", messageLength)
+
 	messageBuf := make([]byte, messageLength)
-	newBytesRead, err := io.ReadFull(sc.netConn, messageBuf)
-	bytesRead += newBytesRead
-	if err != nil {
-		return 0, err
+	if n, err = io.ReadFull(sc.r, messageBuf); err != nil {
+		return n, err
 	}
 
-	return bytesRead, proto.Unmarshal(messageBuf, m)
+	return n, proto.Unmarshal(messageBuf, m)
 }
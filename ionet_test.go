@@ -0,0 +1,225 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/siodb/siodb-go-driver/codec"
+)
+
+// newStreamConn returns a connection reading the given stream.
+func newStreamConn(stream []byte) *siodbConn {
+
+	client, server := net.Pipe()
+	go func() {
+		server.Write(stream)
+		server.Close()
+	}()
+
+	sc := newConn(client, Config{})
+	sc.completed = true
+
+	return sc
+}
+
+// newRowsConn returns a connection reading the rows of a dataset from stream.
+func newRowsConn(stream []byte) *siodbConn {
+
+	sc := newStreamConn(stream)
+	sc.completed = false
+
+	return sc
+}
+
+func packDate(year int, month time.Month, day int, hasTimePart bool) []byte {
+
+	v := uint32(day-1)<<4 | uint32(month-1)<<9 | uint32(year)<<13
+	if hasTimePart {
+		v |= 1
+	}
+	buff := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buff, v)
+
+	return buff
+}
+
+func packTime(hours, minutes, seconds, nanos int) []byte {
+
+	v := uint64(nanos)<<1 | uint64(seconds)<<31 | uint64(minutes)<<37 | uint64(hours)<<43
+	buff := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buff, v)
+
+	return buff[:6]
+}
+
+func TestReadDateTimeFields(t *testing.T) {
+
+	var stream []byte
+	stream = append(stream, packDate(2020, time.February, 29, false)...)
+	stream = append(stream, packTime(23, 59, 58, 123456789)...)
+	stream = append(stream, packDate(1999, time.December, 31, true)...)
+	stream = append(stream, packTime(1, 2, 3, 4)...)
+
+	sc := newStreamConn(stream)
+
+	tests := []struct {
+		columnType ColumnDataType
+		expected   time.Time
+	}{
+		{ColumnDataType_COLUMN_DATA_TYPE_DATE, time.Date(2020, time.February, 29, 0, 0, 0, 0, time.Local)},
+		{ColumnDataType_COLUMN_DATA_TYPE_TIME, time.Date(0, time.January, 1, 23, 59, 58, 123456789, time.Local)},
+		{ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP, time.Date(1999, time.December, 31, 1, 2, 3, 4, time.Local)},
+	}
+
+	for _, test := range tests {
+		value, err := sc.readFieldData(test.columnType)
+		if err != nil {
+			t.Fatalf("readFieldData(%s): %v", test.columnType, err)
+		}
+		if !value.(time.Time).Equal(test.expected) {
+			t.Fatalf("readFieldData(%s): %v != %v", test.columnType, value, test.expected)
+		}
+	}
+}
+
+func appendZigzag(stream []byte, v int64) []byte {
+
+	buff := make([]byte, binary.MaxVarintLen64)
+	return append(stream, buff[:binary.PutVarint(buff, v)]...)
+}
+
+func TestReadTimeWithZoneUnsupported(t *testing.T) {
+
+	for _, columnType := range []ColumnDataType{
+		ColumnDataType_COLUMN_DATA_TYPE_TIME_WITH_TZ,
+		ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP_WITH_TZ,
+	} {
+		sc := newStreamConn(make([]byte, 16))
+		if _, err := sc.readFieldData(columnType); !errors.Is(err, ErrUnsupportedType) {
+			t.Fatalf("readFieldData(%s): ErrUnsupportedType expected, got %v", columnType, err)
+		}
+	}
+}
+
+func TestReadTimestampLocation(t *testing.T) {
+
+	loc := time.FixedZone("UTC+3", 3*3600)
+
+	var stream []byte
+	stream = append(stream, packDate(2021, time.March, 14, true)...)
+	stream = append(stream, packTime(15, 9, 26, 0)...)
+
+	sc := newStreamConn(stream)
+	sc.cfg.Loc = loc
+
+	value, err := sc.readFieldData(ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP)
+	if err != nil {
+		t.Fatalf("readFieldData: %v", err)
+	}
+	if expected := time.Date(2021, time.March, 14, 15, 9, 26, 0, loc); !value.(time.Time).Equal(expected) || value.(time.Time).Location() != loc {
+		t.Fatalf("readFieldData: %v != %v", value, expected)
+	}
+}
+
+// memConn is an in-memory connection serving a stream. It counts the reads
+// that would be system calls on a socket.
+type memConn struct {
+	net.Conn
+	r     *bytes.Reader
+	reads int
+}
+
+func (mc *memConn) Read(p []byte) (int, error) {
+	mc.reads++
+	return mc.r.Read(p)
+}
+
+// wideResultSet returns the description and the rows of a result set of
+// rowCount rows of columnCount columns of mixed types.
+func wideResultSet(b *testing.B, columnCount, rowCount int) ([]*ColumnDescription, []byte) {
+
+	types := []ColumnDataType{
+		ColumnDataType_COLUMN_DATA_TYPE_INT64,
+		ColumnDataType_COLUMN_DATA_TYPE_DOUBLE,
+		ColumnDataType_COLUMN_DATA_TYPE_TEXT,
+		ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP,
+		ColumnDataType_COLUMN_DATA_TYPE_INT32,
+		ColumnDataType_COLUMN_DATA_TYPE_BOOL,
+	}
+	ts := time.Date(2020, time.June, 1, 12, 30, 15, 0, time.UTC)
+	values := map[ColumnDataType]interface{}{
+		ColumnDataType_COLUMN_DATA_TYPE_INT64:     int64(1234567890123),
+		ColumnDataType_COLUMN_DATA_TYPE_DOUBLE:    3.14159,
+		ColumnDataType_COLUMN_DATA_TYPE_TEXT:      "The quick brown fox jumps",
+		ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP: ts,
+		ColumnDataType_COLUMN_DATA_TYPE_INT32:     int32(-42),
+		ColumnDataType_COLUMN_DATA_TYPE_BOOL:      true,
+	}
+
+	columnDesc := make([]*ColumnDescription, columnCount)
+	columns := make([]codec.Column, columnCount)
+	row := make([]interface{}, columnCount)
+	for idx := range columnDesc {
+		columnType := types[idx%len(types)]
+		name := fmt.Sprintf("C%d", idx)
+		columnDesc[idx] = &ColumnDescription{Name: name, Type: columnType, IsNull: idx%4 == 0}
+		columns[idx] = codec.Column{Name: name, Type: codec.DataType(columnType), Nullable: idx%4 == 0}
+		if idx%8 != 0 {
+			row[idx] = values[columnType]
+		}
+	}
+
+	var stream bytes.Buffer
+	for idx := 0; idx < rowCount; idx++ {
+		if err := codec.EncodeRow(&stream, columns, row); err != nil {
+			b.Fatalf("EncodeRow: %v", err)
+		}
+	}
+	codec.EncodeEndOfRows(&stream)
+
+	return columnDesc, stream.Bytes()
+}
+
+func BenchmarkReadRowsWide(b *testing.B) {
+
+	for _, columnCount := range []int{16, 64, 256} {
+		b.Run(fmt.Sprintf("columns=%d", columnCount), func(b *testing.B) {
+
+			columnDesc, stream := wideResultSet(b, columnCount, 1000)
+			dest := make([]driver.Value, columnCount)
+			reads := 0
+
+			b.SetBytes(int64(len(stream)))
+			b.ResetTimer()
+
+			for n := 0; n < b.N; n++ {
+				mc := &memConn{r: bytes.NewReader(stream)}
+				sc := newConn(mc, Config{})
+				sc.nullAllowed = true
+				for {
+					err := sc.readRow(dest, columnDesc)
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						b.Fatalf("readRow: %v", err)
+					}
+				}
+				reads += mc.reads
+			}
+
+			b.ReportMetric(float64(reads)/float64(b.N), "reads/op")
+		})
+	}
+}
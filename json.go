@@ -0,0 +1,71 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON is a JSON document unmarshaled into V on scan and marshaled from V
+// when used as a parameter:
+//
+//	var doc siodb.JSON[Event]
+//	err := db.QueryRowContext(ctx, "SELECT payload FROM test.events WHERE trid = ?", 1).Scan(&doc)
+type JSON[T any] struct {
+	V T
+}
+
+// InvalidJSONError is returned when a JSON document can't be unmarshaled or marshaled.
+type InvalidJSONError struct {
+	Err error
+}
+
+func (ije *InvalidJSONError) Error() string {
+	return fmt.Sprintf("Siodb Driver Error: invalid JSON document: %s", ije.Err.Error())
+}
+
+func (ije *InvalidJSONError) Unwrap() error {
+	return ije.Err
+}
+
+// Scan implements the sql.Scanner interface. A NULL value resets V to its zero value.
+func (j *JSON[T]) Scan(src interface{}) error {
+
+	var doc []byte
+
+	switch v := src.(type) {
+	case nil:
+		var zero T
+		j.V = zero
+		return nil
+	case json.RawMessage:
+		doc = v
+	case []byte:
+		doc = v
+	case string:
+		doc = []byte(v)
+	default:
+		return &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T into a JSON document.", src), Err: ErrUnsupportedType}
+	}
+
+	if err := json.Unmarshal(doc, &j.V); err != nil {
+		return &InvalidJSONError{err}
+	}
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (j JSON[T]) Value() (driver.Value, error) {
+
+	doc, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, &InvalidJSONError{err}
+	}
+
+	return string(doc), nil
+}
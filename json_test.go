@@ -0,0 +1,115 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type jsonEvent struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestReadJSONField(t *testing.T) {
+
+	doc := `{"name":"a","count":2}`
+	sc := newStreamConn(append([]byte{byte(len(doc))}, doc...))
+
+	value, err := sc.readFieldData(ColumnDataType_COLUMN_DATA_TYPE_JSON)
+	if err != nil {
+		t.Fatalf("readFieldData: %v", err)
+	}
+	raw, ok := value.([]byte)
+	if !ok || string(raw) != doc {
+		t.Fatalf("readFieldData: unexpected %T %v", value, value)
+	}
+
+	var event JSON[jsonEvent]
+	if err = event.Scan(raw); err != nil || event.V != (jsonEvent{"a", 2}) {
+		t.Fatalf("Scan: %v, %v", event.V, err)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+
+	var event JSON[jsonEvent]
+	var ije *InvalidJSONError
+	if err := event.Scan([]byte(`{"name":`)); !errors.As(err, &ije) {
+		t.Fatalf("Scan: InvalidJSONError expected, got %v", err)
+	}
+
+	var bad JSON[chan int]
+	if _, err := bad.Value(); !errors.As(err, &ije) {
+		t.Fatalf("Value: InvalidJSONError expected, got %v", err)
+	}
+}
+
+func TestBindJSON(t *testing.T) {
+
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: JSON[jsonEvent]{jsonEvent{"it's", 1}}},
+		{Ordinal: 2, Value: json.RawMessage(`[1,2]`)},
+	}
+
+	text, err := bindText("INSERT INTO t VALUES (?, ?)", args, time.UTC)
+	if err != nil {
+		t.Fatalf("bind: %v", err)
+	}
+	if expected := `INSERT INTO t VALUES ('{"name":"it''s","count":1}', '[1,2]')`; text != expected {
+		t.Fatalf("bind: %q != %q", text, expected)
+	}
+}
+
+// connConnector opens the database/sql connections on sc.
+type connConnector struct {
+	sc *siodbConn
+}
+
+func (c connConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.sc, nil
+}
+
+func (c connConnector) Driver() driver.Driver {
+	return &siodbDriver{}
+}
+
+func TestScanJSONColumn(t *testing.T) {
+
+	doc := `{"name":"a","count":2}`
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID: 1,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "A", Type: ColumnDataType_COLUMN_DATA_TYPE_JSON},
+			{Name: "B", Type: ColumnDataType_COLUMN_DATA_TYPE_JSON},
+			{Name: "C", Type: ColumnDataType_COLUMN_DATA_TYPE_JSON},
+		},
+	})
+	stream = append(stream, byte(3*(1+len(doc))))
+	for idx := 0; idx < 3; idx++ {
+		stream = append(append(stream, byte(len(doc))), doc...)
+	}
+	stream = append(stream, 0)
+
+	db := sql.OpenDB(connConnector{newFakeServerConn(t, stream)})
+	defer db.Close()
+
+	var s string
+	var raw json.RawMessage
+	var event JSON[jsonEvent]
+	if err := db.QueryRowContext(context.Background(), "SELECT a, b, c FROM t").Scan(&s, &raw, &event); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if s != doc || string(raw) != doc || event.V != (jsonEvent{"a", 2}) {
+		t.Fatalf("Scan: unexpected %q, %q, %v", s, raw, event.V)
+	}
+}
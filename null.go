@@ -0,0 +1,260 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// NullUint64 represents a BIGUINT that may be null, over the full uint64 range.
+type NullUint64 struct {
+	Uint64 uint64
+	Valid  bool // Valid is true if Uint64 is not NULL
+}
+
+// NullUint32 represents a UINT that may be null.
+type NullUint32 struct {
+	Uint32 uint32
+	Valid  bool // Valid is true if Uint32 is not NULL
+}
+
+// NullUint16 represents a SMALLUINT that may be null.
+type NullUint16 struct {
+	Uint16 uint16
+	Valid  bool // Valid is true if Uint16 is not NULL
+}
+
+// NullUint8 represents a TINYUINT that may be null.
+type NullUint8 struct {
+	Uint8 uint8
+	Valid bool // Valid is true if Uint8 is not NULL
+}
+
+// NullInt8 represents a TINYINT that may be null.
+type NullInt8 struct {
+	Int8  int8
+	Valid bool // Valid is true if Int8 is not NULL
+}
+
+// NullBigInt represents an integer that may be null with arbitrary precision,
+// e.g. to compute on BIGUINT values without overflow.
+type NullBigInt struct {
+	Int   big.Int
+	Valid bool // Valid is true if Int is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullUint64) Scan(value interface{}) (err error) {
+	n.Uint64, n.Valid, err = scanUnsigned(value, 64)
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullUint64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Uint64, nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullUint32) Scan(value interface{}) error {
+	u, valid, err := scanUnsigned(value, 32)
+	n.Uint32, n.Valid = uint32(u), valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullUint32) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Uint32), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullUint16) Scan(value interface{}) error {
+	u, valid, err := scanUnsigned(value, 16)
+	n.Uint16, n.Valid = uint16(u), valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullUint16) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Uint16), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullUint8) Scan(value interface{}) error {
+	u, valid, err := scanUnsigned(value, 8)
+	n.Uint8, n.Valid = uint8(u), valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullUint8) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Uint8), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullInt8) Scan(value interface{}) error {
+	i, valid, err := scanSigned(value, 8)
+	n.Int8, n.Valid = int8(i), valid
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (n NullInt8) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.Int8), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullBigInt) Scan(value interface{}) error {
+
+	n.Valid = true
+	switch v := value.(type) {
+	case nil:
+		n.Int.SetInt64(0)
+		n.Valid = false
+	case uint64:
+		n.Int.SetUint64(v)
+	case uint32, uint16, uint8:
+		u, _, _ := scanUnsigned(v, 64)
+		n.Int.SetUint64(u)
+	case int64, int32, int16, int8:
+		i, _, _ := scanSigned(v, 64)
+		n.Int.SetInt64(i)
+	case string:
+		return n.setString(v)
+	case []byte:
+		return n.setString(string(v))
+	default:
+		n.Valid = false
+		return &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T into a NullBigInt.", value), Err: ErrUnsupportedType}
+	}
+
+	return nil
+}
+
+func (n *NullBigInt) setString(s string) error {
+
+	if _, ok := n.Int.SetString(s, 10); !ok {
+		n.Valid = false
+		return &DriverError{Message: "Can't scan '" + s + "' into a NullBigInt."}
+	}
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface. Values outside of the int64
+// and uint64 ranges are bound as their decimal representation.
+func (n NullBigInt) Value() (driver.Value, error) {
+
+	switch {
+	case !n.Valid:
+		return nil, nil
+	case n.Int.IsInt64():
+		return n.Int.Int64(), nil
+	case n.Int.IsUint64():
+		return n.Int.Uint64(), nil
+	default:
+		return n.Int.String(), nil
+	}
+}
+
+// scanUnsigned converts a scanned value to an unsigned integer of the given
+// size, failing instead of truncating.
+func scanUnsigned(value interface{}, bitSize int) (u uint64, valid bool, err error) {
+
+	switch v := value.(type) {
+	case nil:
+		return 0, false, nil
+	case uint64:
+		u = v
+	case uint32:
+		u = uint64(v)
+	case uint16:
+		u = uint64(v)
+	case uint8:
+		u = uint64(v)
+	case int64, int32, int16, int8:
+		var i int64
+		if i, _, err = scanSigned(v, 64); err != nil {
+			return 0, false, err
+		}
+		if i < 0 {
+			return 0, false, &DriverError{Message: fmt.Sprintf("Negative value %d can't be scanned into an unsigned integer.", i)}
+		}
+		u = uint64(i)
+	case string:
+		if u, err = strconv.ParseUint(v, 10, bitSize); err != nil {
+			return 0, false, &DriverError{Message: "Can't scan '" + v + "' into an unsigned integer.", Err: err}
+		}
+	case []byte:
+		return scanUnsigned(string(v), bitSize)
+	default:
+		return 0, false, &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T into an unsigned integer.", value), Err: ErrUnsupportedType}
+	}
+
+	if bitSize < 64 && u > uint64(1)<<uint(bitSize)-1 {
+		return 0, false, &DriverError{Message: fmt.Sprintf("Value %d overflows uint%d.", u, bitSize)}
+	}
+
+	return u, true, nil
+}
+
+// scanSigned converts a scanned value to a signed integer of the given size,
+// failing instead of truncating.
+func scanSigned(value interface{}, bitSize int) (i int64, valid bool, err error) {
+
+	switch v := value.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		i = v
+	case int32:
+		i = int64(v)
+	case int16:
+		i = int64(v)
+	case int8:
+		i = int64(v)
+	case uint64, uint32, uint16, uint8:
+		var u uint64
+		if u, _, err = scanUnsigned(v, 64); err != nil {
+			return 0, false, err
+		}
+		if u > math.MaxInt64 {
+			return 0, false, &DriverError{Message: fmt.Sprintf("Value %d overflows int64.", u)}
+		}
+		i = int64(u)
+	case string:
+		if i, err = strconv.ParseInt(v, 10, bitSize); err != nil {
+			return 0, false, &DriverError{Message: "Can't scan '" + v + "' into an integer.", Err: err}
+		}
+	case []byte:
+		return scanSigned(string(v), bitSize)
+	default:
+		return 0, false, &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T into an integer.", value), Err: ErrUnsupportedType}
+	}
+
+	if bitSize < 64 && (i < -(int64(1)<<uint(bitSize-1)) || i > int64(1)<<uint(bitSize-1)-1) {
+		return 0, false, &DriverError{Message: fmt.Sprintf("Value %d overflows int%d.", i, bitSize)}
+	}
+
+	return i, true, nil
+}
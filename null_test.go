@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestNullUnsigned(t *testing.T) {
+
+	var n64 NullUint64
+	if err := n64.Scan(uint64(math.MaxUint64)); err != nil || !n64.Valid || n64.Uint64 != math.MaxUint64 {
+		t.Fatalf("NullUint64.Scan: %+v, %v", n64, err)
+	}
+	if err := n64.Scan(nil); err != nil || n64.Valid {
+		t.Fatalf("NullUint64.Scan(nil): %+v, %v", n64, err)
+	}
+	if err := n64.Scan(int64(-1)); err == nil {
+		t.Fatalf("NullUint64.Scan(-1): error expected")
+	}
+
+	var n8 NullUint8
+	if err := n8.Scan(uint8(255)); err != nil || !n8.Valid || n8.Uint8 != 255 {
+		t.Fatalf("NullUint8.Scan: %+v, %v", n8, err)
+	}
+	if err := n8.Scan(uint16(256)); err == nil {
+		t.Fatalf("NullUint8.Scan(256): error expected")
+	}
+
+	var i8 NullInt8
+	if err := i8.Scan(int8(-128)); err != nil || !i8.Valid || i8.Int8 != -128 {
+		t.Fatalf("NullInt8.Scan: %+v, %v", i8, err)
+	}
+	if err := i8.Scan(int64(128)); err == nil {
+		t.Fatalf("NullInt8.Scan(128): error expected")
+	}
+
+	var n16 NullUint16
+	var n32 NullUint32
+	if err := n16.Scan(uint16(65535)); err != nil || n16.Uint16 != 65535 {
+		t.Fatalf("NullUint16.Scan: %+v, %v", n16, err)
+	}
+	if err := n32.Scan("4294967295"); err != nil || n32.Uint32 != math.MaxUint32 {
+		t.Fatalf("NullUint32.Scan: %+v, %v", n32, err)
+	}
+}
+
+func TestNullBigInt(t *testing.T) {
+
+	var n NullBigInt
+	if err := n.Scan(uint64(math.MaxUint64)); err != nil || !n.Valid {
+		t.Fatalf("NullBigInt.Scan: %v", err)
+	}
+	n.Int.Add(&n.Int, big.NewInt(1))
+	if value, _ := n.Value(); value != "18446744073709551616" {
+		t.Fatalf("NullBigInt.Value: %v", value)
+	}
+	n.Int.SetInt64(-5)
+	if value, _ := n.Value(); value != int64(-5) {
+		t.Fatalf("NullBigInt.Value: %v", value)
+	}
+}
+
+func TestBindNullTypes(t *testing.T) {
+
+	sc := &siodbConn{}
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: NullUint64{Uint64: math.MaxUint64, Valid: true}},
+		{Ordinal: 2, Value: NullInt8{}},
+		{Ordinal: 3, Value: (*UUID)(nil)},
+	}
+	for idx := range args {
+		if err := sc.CheckNamedValue(&args[idx]); err != nil {
+			t.Fatalf("CheckNamedValue(%v): %v", args[idx].Value, err)
+		}
+	}
+
+	text, err := bindText("INSERT INTO t VALUES (?, ?, ?)", args, time.UTC)
+	if err != nil || text != "INSERT INTO t VALUES (18446744073709551615, NULL, NULL)" {
+		t.Fatalf("bind: %q, %v", text, err)
+	}
+}
@@ -6,11 +6,14 @@ package siodb
 
 import (
 	"database/sql/driver"
+	"io"
+	"reflect"
 )
 
 type siodbRows struct {
 	sc         *siodbConn
 	columnDesc []*ColumnDescription
+	watcher    *cancelWatcher
 }
 
 func (rows *siodbRows) Columns() []string {
@@ -26,18 +29,78 @@ func (rows *siodbRows) Columns() []string {
 
 func (rows *siodbRows) Next(dest []driver.Value) error {
 
-	return rows.sc.readRow(dest, rows.columnDesc)
+	err := rows.sc.readRow(dest, rows.columnDesc)
+	if err == io.EOF {
+		return err
+	}
+
+	return rows.watcher.check(err)
 
 }
 
 func (rows *siodbRows) Close() (err error) {
 
+	defer rows.watcher.stop()
+
+	if !rows.sc.bad {
+		if _, err = rows.sc.discardResponses(); err != nil {
+			return rows.watcher.check(err)
+		}
+	}
+
+	return nil
+
+}
+
+// HasNextResultSet See https://golang.org/pkg/database/sql/driver/#RowsNextResultSet
+func (rows *siodbRows) HasNextResultSet() bool {
+	return rows.sc.moreResponses()
+}
+
+// NextResultSet moves to the dataset of the next statement of the command.
+// See https://golang.org/pkg/database/sql/driver/#RowsNextResultSet
+func (rows *siodbRows) NextResultSet() error {
+
+	var sr ServerResponse
+	var err error
+
+	if !rows.sc.moreResponses() {
+		return io.EOF
+	}
+
 	if !rows.sc.completed {
 		if _, err = rows.sc.cleanupBuffer(); err != nil {
-			return err
+			return rows.watcher.check(err)
 		}
 	}
 
-	return nil
+	if sr, err = rows.sc.readServer(); err != nil {
+		return rows.watcher.check(err)
+	}
+	rows.columnDesc = sr.ColumnDescription
+
+	return checkServerError(sr.Message)
+}
+
+// ColumnTypeDatabaseTypeName See https://golang.org/pkg/database/sql/driver/#RowsColumnTypeDatabaseTypeName
+func (rows *siodbRows) ColumnTypeDatabaseTypeName(index int) string {
+	return columnTypeName(rows.columnDesc[index].GetType())
+}
+
+// ColumnTypeScanType See https://golang.org/pkg/database/sql/driver/#RowsColumnTypeScanType
+func (rows *siodbRows) ColumnTypeScanType(index int) reflect.Type {
+	if _, ok := rows.sc.cfg.Decoders.Lookup(rows.columnDesc[index].GetType()); ok {
+		return scanTypeInterface
+	}
+	return rows.sc.cfg.ValueMode.scanType(rows.columnDesc[index].GetType())
+}
+
+// ColumnTypeNullable See https://golang.org/pkg/database/sql/driver/#RowsColumnTypeNullable
+func (rows *siodbRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return rows.columnDesc[index].GetIsNull(), true
+}
 
+// ColumnTypeLength See https://golang.org/pkg/database/sql/driver/#RowsColumnTypeLength
+func (rows *siodbRows) ColumnTypeLength(index int) (length int64, ok bool) {
+	return columnLength(rows.columnDesc[index].GetType())
 }
@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+type siodbStmt struct {
+	sc *siodbConn
+	pq *parsedQuery
+}
+
+func (stmt *siodbStmt) Close() error {
+	stmt.sc = nil
+	return nil
+}
+
+// NumInput returns the number of placeholders of the statement, or -1 when
+// it uses named placeholders.
+func (stmt *siodbStmt) NumInput() int {
+	return stmt.pq.numInput
+}
+
+func (stmt *siodbStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+
+	if stmt.sc == nil {
+		return nil, &DriverError{Message: "Statement is closed."}
+	}
+
+	return stmt.sc.exec(ctx, stmt.pq, args)
+}
+
+func (stmt *siodbStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+
+	if stmt.sc == nil {
+		return nil, &DriverError{Message: "Statement is closed."}
+	}
+
+	return stmt.sc.query(ctx, stmt.pq, args)
+}
+
+// Exec See https://golang.org/pkg/database/sql/driver/#Stmt
+func (stmt *siodbStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return stmt.ExecContext(context.Background(), namedValues(args))
+}
+
+// Query See https://golang.org/pkg/database/sql/driver/#Stmt
+func (stmt *siodbStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return stmt.QueryContext(context.Background(), namedValues(args))
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+
+	named := make([]driver.NamedValue, len(args))
+	for idx, arg := range args {
+		named[idx] = driver.NamedValue{
+			Ordinal: idx + 1,
+			Value:   arg,
+		}
+	}
+
+	return named
+}
@@ -0,0 +1,386 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/siodb/siodb-go-driver/codec"
+)
+
+// StreamQueryer is implemented by the driver connections. QueryStream runs a
+// query whose BINARY and TEXT values can be read from the connection while
+// they are received, instead of being loaded in memory. It is reached
+// through sql.Conn.Raw:
+//
+//	err = conn.Raw(func(driverConn interface{}) error {
+//		rows, err := driverConn.(siodb.StreamQueryer).QueryStream(ctx, "SELECT id, data FROM t")
+//		...
+//	})
+type StreamQueryer interface {
+	QueryStream(ctx context.Context, query string, args ...interface{}) (*StreamRows, error)
+}
+
+// StreamRows is the result of QueryStream. A BINARY or TEXT column scanned
+// into a *BlobReader is read from the connection through the reader, and the
+// columns following it are decoded and assigned once the reader is consumed
+// or closed. The rows must be closed before the connection is used again.
+type StreamRows struct {
+	sc         *siodbConn
+	columnDesc []*ColumnDescription
+	watcher    *cancelWatcher
+	bitmask    codec.NullBitmask
+	dest       []interface{} // Destinations of the current row, nil if not scanned
+	next       int           // Index of the next column to read
+	inRow      bool
+	scanned    bool
+	blob       *BlobReader // Reader of the current column, if any
+	done       bool
+	closed     bool
+	err        error
+}
+
+// BlobReader reads a BINARY or TEXT value from the connection. For TEXT
+// values the reader returns the UTF-8 bytes.
+type BlobReader struct {
+	rows *StreamRows
+	r    io.LimitedReader
+	size int64
+	done bool
+}
+
+// QueryStream See StreamQueryer.
+func (sc *siodbConn) QueryStream(ctx context.Context, query string, args ...interface{}) (*StreamRows, error) {
+
+	namedArgs, err := sc.namedValues(args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sc.query(ctx, sc.parseQuery(query), namedArgs)
+	if err != nil {
+		return nil, err
+	}
+	sr := rows.(*siodbRows)
+
+	return &StreamRows{
+		sc:         sc,
+		columnDesc: sr.columnDesc,
+		watcher:    sr.watcher,
+	}, nil
+}
+
+// namedValues converts the arguments as database/sql does for QueryContext:
+// CheckNamedValue first, and else the default conversion.
+func (sc *siodbConn) namedValues(args []interface{}) ([]driver.NamedValue, error) {
+
+	namedArgs := make([]driver.NamedValue, len(args))
+	for idx, arg := range args {
+		nv := &namedArgs[idx]
+		nv.Ordinal = idx + 1
+		if named, ok := arg.(sql.NamedArg); ok {
+			nv.Name = named.Name
+			arg = named.Value
+		}
+		nv.Value = arg
+
+		err := sc.CheckNamedValue(nv)
+		if err == driver.ErrSkip {
+			nv.Value, err = driver.DefaultParameterConverter.ConvertValue(arg)
+		}
+		if err != nil {
+			return nil, &DriverError{Message: fmt.Sprintf("Can't convert argument %d | %s", idx+1, err.Error()), Err: err}
+		}
+	}
+
+	return namedArgs, nil
+}
+
+// Columns returns the column names.
+func (rows *StreamRows) Columns() []string {
+
+	columns := make([]string, len(rows.columnDesc))
+	for idx, column := range rows.columnDesc {
+		columns[idx] = column.Name
+	}
+
+	return columns
+}
+
+// ColumnDescriptions returns the descriptions of the columns.
+func (rows *StreamRows) ColumnDescriptions() []*ColumnDescription {
+	return rows.columnDesc
+}
+
+// Next moves to the next row. What is left of the current row, including an
+// unread BlobReader, is read and dropped. It returns false at the end of the
+// rows or on error, see Err.
+func (rows *StreamRows) Next() bool {
+
+	if rows.closed || rows.done || rows.err != nil {
+		return false
+	}
+
+	// The destinations of what is left of the row are not assigned.
+	rows.dest = nil
+	if err := rows.finishRow(); err != nil {
+		return false
+	}
+
+	// No row stream follows a response without dataset.
+	if rows.sc.completed {
+		rows.done = true
+		return false
+	}
+
+	rowLength, err := rows.sc.readVarint()
+	if err != nil {
+		rows.fail(&DriverError{Message: "Unable to read the row size.", Err: err})
+		return false
+	}
+	if rowLength == 0 {
+		rows.sc.completed = true
+		rows.done = true
+		return false
+	}
+
+	rows.bitmask = nil
+	if rows.sc.nullAllowed {
+		if rows.bitmask, err = codec.ReadNullBitmask(rows.sc.r, len(rows.columnDesc)); err != nil {
+			rows.fail(&DriverError{Message: "Fail to read the bitmask byte(s).", Err: err})
+			return false
+		}
+	}
+
+	rows.next = 0
+	rows.dest = nil
+	rows.inRow = true
+	rows.scanned = false
+
+	return true
+}
+
+// Scan assigns the columns of the current row to dest with the conversions
+// of sql.Rows.Scan. A *BlobReader destination of a BINARY or TEXT column
+// receives the reader of the value, or nil for NULL, and the following
+// destinations are assigned once the reader is consumed or closed.
+func (rows *StreamRows) Scan(dest ...interface{}) error {
+
+	if rows.err != nil {
+		return rows.err
+	}
+	if !rows.inRow || rows.scanned {
+		return &DriverError{Message: "Scan called without calling Next."}
+	}
+	if len(dest) != len(rows.columnDesc) {
+		return &DriverError{Message: fmt.Sprintf("Expected %d destination arguments in Scan, not %d.", len(rows.columnDesc), len(dest))}
+	}
+
+	for idx, column := range rows.columnDesc {
+		if _, ok := dest[idx].(**BlobReader); ok && !isStreamable(column.Type) {
+			return &DriverError{Message: "Column " + column.Name + " of type " + columnTypeName(column.Type) + " can't be read with a BlobReader.", Err: ErrUnsupportedType}
+		}
+		if dv := reflect.ValueOf(dest[idx]); dv.Kind() != reflect.Ptr || dv.IsNil() {
+			return &DriverError{Message: fmt.Sprintf("Destination of column %s is not a pointer: %T.", column.Name, dest[idx])}
+		}
+	}
+
+	rows.dest = dest
+	rows.scanned = true
+
+	return rows.resume()
+}
+
+// Err returns the error that ended the iteration, if any.
+func (rows *StreamRows) Err() error {
+	return rows.err
+}
+
+// Close drops the rows left and releases the connection.
+func (rows *StreamRows) Close() error {
+
+	if rows.closed {
+		return nil
+	}
+	rows.closed = true
+
+	defer rows.watcher.stop()
+
+	if rows.sc.bad {
+		return nil
+	}
+
+	// Nothing is assigned anymore.
+	rows.dest = nil
+	if err := rows.finishRow(); err != nil {
+		return err
+	}
+
+	if _, err := rows.sc.discardResponses(); err != nil {
+		return rows.watcher.check(err)
+	}
+
+	return nil
+}
+
+func isStreamable(columnType ColumnDataType) bool {
+	return columnType == ColumnDataType_COLUMN_DATA_TYPE_BINARY || columnType == ColumnDataType_COLUMN_DATA_TYPE_TEXT
+}
+
+func (rows *StreamRows) fail(err error) error {
+
+	rows.sc.bad = true
+	rows.err = rows.watcher.check(err)
+
+	return rows.err
+}
+
+// finishRow reads what is left of the current row.
+func (rows *StreamRows) finishRow() error {
+
+	for rows.inRow {
+		var err error
+		if rows.blob != nil {
+			err = rows.blob.Close()
+		} else {
+			err = rows.resume()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resume reads the columns of the current row up to the next streamed one.
+// Streamable values without destination are skipped without being loaded.
+func (rows *StreamRows) resume() error {
+
+	var assignErr error
+
+	for rows.next < len(rows.columnDesc) {
+
+		idx := rows.next
+		column := rows.columnDesc[idx]
+		rows.next++
+
+		var dest interface{}
+		if rows.dest != nil {
+			dest = rows.dest[idx]
+		}
+		isNull := rows.bitmask.IsNull(idx)
+
+		if blobDest, ok := dest.(**BlobReader); ok || (dest == nil && isStreamable(column.Type)) {
+			if isNull {
+				if ok {
+					*blobDest = nil
+				}
+				continue
+			}
+			size, err := rows.sc.readVarint()
+			if err != nil {
+				return rows.fail(&DriverError{Message: "Fail to read field " + column.Name + " from current row | " + err.Error(), Err: err})
+			}
+			blob := &BlobReader{rows: rows, r: io.LimitedReader{R: rows.sc.r, N: int64(size)}, size: int64(size)}
+			if !ok {
+				if err = blob.Close(); err != nil {
+					return err
+				}
+				continue
+			}
+			rows.blob = blob
+			*blobDest = blob
+			return assignErr
+		}
+
+		var value driver.Value
+		if !isNull {
+			var err error
+			if value, err = rows.sc.readValue(column.Type, column.Attribute); err != nil {
+				return rows.fail(&DriverError{Message: "Fail to read field " + column.Name + " from current row | " + err.Error(), Err: err})
+			}
+			if value, err = rows.sc.cfg.ValueMode.mapValue(column.Type, column.Attribute, value); err != nil {
+				return rows.fail(&DriverError{Message: "Fail to map field " + column.Name + " from current row | " + err.Error(), Err: err})
+			}
+		}
+
+		// Go on reading the row on assignment errors to stay in sync.
+		if dest != nil && assignErr == nil {
+			if err := convertAssign(dest, value); err != nil {
+				assignErr = &DriverError{Message: "Can't scan column " + column.Name + " | " + err.Error(), Err: err}
+			}
+		}
+	}
+
+	rows.inRow = false
+
+	return assignErr
+}
+
+// Size returns the size of the value in bytes.
+func (b *BlobReader) Size() int64 {
+	return b.size
+}
+
+// Read reads the value. At the end of the value the following columns of the
+// row are read and an error assigning them is returned instead of io.EOF.
+func (b *BlobReader) Read(p []byte) (int, error) {
+
+	if b.done {
+		return 0, io.EOF
+	}
+
+	n, err := b.r.Read(p)
+	if err == io.EOF && b.r.N > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil && err != io.EOF {
+		return n, b.rows.fail(&DriverError{Message: "Fail to read the value | " + err.Error(), Err: err})
+	}
+
+	if b.r.N == 0 {
+		if err = b.finish(); err != nil {
+			return n, err
+		}
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// Close drops what is left of the value and reads the following columns of
+// the row.
+func (b *BlobReader) Close() error {
+
+	if b.done {
+		return nil
+	}
+
+	if _, err := io.Copy(io.Discard, &b.r); err != nil {
+		return b.rows.fail(&DriverError{Message: "Fail to read the value | " + err.Error(), Err: err})
+	}
+	if b.r.N > 0 {
+		return b.rows.fail(&DriverError{Message: "Fail to read the value.", Err: io.ErrUnexpectedEOF})
+	}
+
+	return b.finish()
+}
+
+func (b *BlobReader) finish() error {
+
+	b.done = true
+	if b.rows.blob == b {
+		b.rows.blob = nil
+		return b.rows.resume()
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestQueryStream(t *testing.T) {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID: 1,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "ID", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+			{Name: "DATA", Type: ColumnDataType_COLUMN_DATA_TYPE_BINARY, IsNull: true},
+			{Name: "NAME", Type: ColumnDataType_COLUMN_DATA_TYPE_TEXT},
+		},
+	})
+	stream = append(stream, 10, 0, 1, 5, 'h', 'e', 'l', 'l', 'o', 1, 'x')
+	stream = append(stream, 4, 2, 2, 1, 'y')
+	stream = append(stream, 8, 0, 3, 3, 'a', 'b', 'c', 1, 'z')
+	stream = append(stream, 0)
+
+	sc := newFakeServerConn(t, stream)
+	defer sc.Close()
+
+	rows, err := sc.QueryStream(context.Background(), "SELECT id, data, name FROM t WHERE id > ?", 0)
+	if err != nil {
+		t.Fatalf("QueryStream: %v", err)
+	}
+
+	var id int64
+	var blob *BlobReader
+	var name string
+
+	if !rows.Next() {
+		t.Fatalf("Next: %v", rows.Err())
+	}
+	if err = rows.Scan(&id, &blob, &name); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if id != 1 || blob == nil || blob.Size() != 5 || name != "" {
+		t.Fatalf("Scan: %d, %v, %q", id, blob, name)
+	}
+	data, err := ioutil.ReadAll(blob)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "hello" || name != "x" {
+		t.Fatalf("ReadAll: %q, %q", data, name)
+	}
+
+	if !rows.Next() {
+		t.Fatalf("Next: %v", rows.Err())
+	}
+	if err = rows.Scan(&id, &blob, &name); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if id != 2 || blob != nil || name != "y" {
+		t.Fatalf("Scan: %d, %v, %q", id, blob, name)
+	}
+
+	// Not scanned, the value is skipped.
+	if !rows.Next() {
+		t.Fatalf("Next: %v", rows.Err())
+	}
+	if rows.Next() {
+		t.Fatalf("Next: unexpected row")
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if err = rows.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !sc.IsValid() || !sc.completed {
+		t.Fatalf("Close: the connection can't be reused")
+	}
+}
+
+func TestQueryStreamNoDataset(t *testing.T) {
+
+	stream := appendResponse(t, nil, &ServerResponse{RequestID: 1, AffectedRowCount: 1})
+
+	sc := newFakeServerConn(t, stream)
+	defer sc.Close()
+
+	rows, err := sc.QueryStream(context.Background(), "DELETE FROM t WHERE id = ?", 1)
+	if err != nil {
+		t.Fatalf("QueryStream: %v", err)
+	}
+	if rows.Next() {
+		t.Fatalf("Next: unexpected row")
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatalf("Err: %v", err)
+	}
+	if err = rows.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !sc.IsValid() {
+		t.Fatalf("Close: the connection can't be reused")
+	}
+}
+
+func TestQueryStreamCloseMidRow(t *testing.T) {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID: 1,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "DATA", Type: ColumnDataType_COLUMN_DATA_TYPE_BINARY},
+			{Name: "ID", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+		},
+	})
+	stream = append(stream, 5, 3, 'a', 'b', 'c', 1)
+	stream = append(stream, 5, 3, 'd', 'e', 'f', 2)
+	stream = append(stream, 0)
+
+	sc := newFakeServerConn(t, stream)
+	defer sc.Close()
+
+	rows, err := sc.QueryStream(context.Background(), "SELECT data, id FROM t")
+	if err != nil {
+		t.Fatalf("QueryStream: %v", err)
+	}
+
+	var id int64
+	var blob *BlobReader
+	if !rows.Next() {
+		t.Fatalf("Next: %v", rows.Err())
+	}
+	if err = rows.Scan(&blob, &id); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	buff := make([]byte, 1)
+	if _, err = blob.Read(buff); err != nil || buff[0] != 'a' {
+		t.Fatalf("Read: %q, %v", buff, err)
+	}
+	if err = blob.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if id != 1 {
+		t.Fatalf("Close: id is %d", id)
+	}
+
+	if err = rows.Scan(&blob, &id); err == nil {
+		t.Fatalf("Scan: no error when scanning a row twice")
+	}
+	if err = rows.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !sc.IsValid() || !sc.completed {
+		t.Fatalf("Close: the connection can't be reused")
+	}
+}
+
+// scanStream returns the response to a query of one row: 42, 'abc', NULL, '<a/>'.
+func scanStream(t *testing.T) []byte {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{
+		RequestID: 1,
+		ColumnDescription: []*ColumnDescription{
+			{Name: "N", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+			{Name: "T", Type: ColumnDataType_COLUMN_DATA_TYPE_TEXT},
+			{Name: "E", Type: ColumnDataType_COLUMN_DATA_TYPE_TEXT, IsNull: true},
+			{Name: "X", Type: ColumnDataType_COLUMN_DATA_TYPE_XML},
+		},
+	})
+	stream = append(stream, 11, 0x04, 42, 3, 'a', 'b', 'c', 4, '<', 'a', '/', '>')
+	stream = append(stream, 0)
+
+	return stream
+}
+
+func TestQueryStreamScanLikeSQL(t *testing.T) {
+
+	tests := []func() []interface{}{
+		func() []interface{} { return []interface{}{new(string), new([]byte), new(sql.NullString), new(string)} },
+		func() []interface{} { return []interface{}{new(int32), new(string), new(*string), new(XML)} },
+		func() []interface{} { return []interface{}{new(uint8), new(sql.RawBytes), new([]byte), new([]byte)} },
+		func() []interface{} {
+			return []interface{}{new(interface{}), new(interface{}), new(interface{}), new(interface{})}
+		},
+		func() []interface{} { return []interface{}{new(float64), new(NullInt8), new(string), new(string)} },
+		func() []interface{} { return []interface{}{new(int8), new(int64), new(int), new(bool)} },
+	}
+
+	for idx, test := range tests {
+		sqlDest, streamDest := test(), test()
+
+		db := sql.OpenDB(connConnector{newFakeServerConn(t, scanStream(t))})
+		sqlRows, err := db.QueryContext(context.Background(), "SELECT n, t, e, x FROM t")
+		if err != nil {
+			t.Fatalf("QueryContext: %v", err)
+		}
+		if !sqlRows.Next() {
+			t.Fatalf("Next: %v", sqlRows.Err())
+		}
+		sqlErr := sqlRows.Scan(sqlDest...)
+		sqlRows.Close()
+		db.Close()
+
+		sc := newFakeServerConn(t, scanStream(t))
+		rows, err := sc.QueryStream(context.Background(), "SELECT n, t, e, x FROM t")
+		if err != nil {
+			t.Fatalf("QueryStream: %v", err)
+		}
+		if !rows.Next() {
+			t.Fatalf("Next: %v", rows.Err())
+		}
+		streamErr := rows.Scan(streamDest...)
+		if err = rows.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		sc.Close()
+
+		if (sqlErr == nil) != (streamErr == nil) {
+			t.Fatalf("Scan %d: sql.Rows error %v, StreamRows error %v", idx, sqlErr, streamErr)
+		}
+		if sqlErr != nil {
+			continue
+		}
+		for col := range sqlDest {
+			if !reflect.DeepEqual(sqlDest[col], streamDest[col]) {
+				t.Fatalf("Scan %d, column %d: sql.Rows %#v, StreamRows %#v", idx, col, sqlDest[col], streamDest[col])
+			}
+		}
+	}
+}
+
+type testLevel int
+
+func TestQueryStreamArgs(t *testing.T) {
+
+	stream := appendResponse(t, nil, &ServerResponse{RequestID: 1})
+	bc := &batchConn{responses: bytes.NewReader(stream)}
+	sc := newConn(bc, Config{})
+	sc.completed = true
+
+	rows, err := sc.QueryStream(context.Background(), "SELECT * FROM t WHERE a = ? AND b = ? AND c = ? AND d = :d",
+		NullInt8{Int8: -3, Valid: true}, JSON[jsonEvent]{jsonEvent{"a", 1}}, testLevel(5), sql.Named("d", NullUint8{}))
+	if err != nil {
+		t.Fatalf("QueryStream: %v", err)
+	}
+	if err = rows.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	expected := `SELECT * FROM t WHERE a = -3 AND b = '{"name":"a","count":1}' AND c = 5 AND d = NULL`
+	if commands := bc.commands(); len(commands) != 1 || commands[0] != expected {
+		t.Fatalf("QueryStream: commands sent %q", commands)
+	}
+
+	if _, err = sc.QueryStream(context.Background(), "SELECT ?", struct{}{}); err == nil {
+		t.Fatalf("QueryStream: no error for an unsupported argument")
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// StructField is a named attribute of a structured value. Value is nil for NULL.
+type StructField struct {
+	Name  string
+	Value interface{}
+}
+
+// Struct is a value of a STRUCT column: its attributes in the order of the
+// column description.
+type Struct struct {
+	Fields []StructField
+}
+
+// Get returns the value of the named attribute.
+func (s Struct) Get(name string) (value interface{}, ok bool) {
+
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Map returns the attributes by name. Nested structured values are also
+// returned as maps.
+func (s Struct) Map() map[string]interface{} {
+
+	m := make(map[string]interface{}, len(s.Fields))
+	for _, field := range s.Fields {
+		if nested, ok := field.Value.(Struct); ok {
+			m[field.Name] = nested.Map()
+		} else {
+			m[field.Name] = field.Value
+		}
+	}
+
+	return m
+}
+
+// ScanInto copies the attributes into the fields of the struct pointed to by
+// dest. A field matches the attribute named in its `siodb` tag, or else the
+// attribute with the same name regardless of case. Fields tagged "-" and
+// attributes without a matching field are skipped.
+func (s Struct) ScanInto(dest interface{}) error {
+
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return &DriverError{Message: fmt.Sprintf("Can't scan a structured value into %T, a pointer to a struct is expected.", dest)}
+	}
+	dv = dv.Elem()
+
+	for _, field := range s.Fields {
+		target, ok := structFieldByName(dv, field.Name)
+		if !ok {
+			continue
+		}
+		if err := assignValue(target, field.Value); err != nil {
+			return &DriverError{Message: "Can't scan attribute " + field.Name + " | " + err.Error(), Err: err}
+		}
+	}
+
+	return nil
+}
+
+func structFieldByName(dv reflect.Value, name string) (reflect.Value, bool) {
+
+	dt := dv.Type()
+	for idx := 0; idx < dt.NumField(); idx++ {
+		sf := dt.Field(idx)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		tag := strings.Split(sf.Tag.Get("siodb"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == name || (tag == "" && strings.EqualFold(sf.Name, name)) {
+			return dv.Field(idx), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// assignValue sets target to value, converting between numeric types when the
+// value fits, and between string types.
+func assignValue(target reflect.Value, value interface{}) error {
+
+	if scanner, ok := target.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if nested, ok := value.(Struct); ok && target.Kind() == reflect.Struct {
+		return nested.ScanInto(target.Addr().Interface())
+	}
+	if target.Kind() == reflect.Ptr {
+		ptr := reflect.New(target.Type().Elem())
+		if err := assignValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(ptr)
+		return nil
+	}
+
+	sv := reflect.ValueOf(value)
+	switch {
+	case sv.Type().AssignableTo(target.Type()):
+		target.Set(sv)
+	case target.Kind() == reflect.String && (sv.Kind() == reflect.String || sv.Type() == scanTypeBytes):
+		// XML, UUID and JSON values into string fields, or into named string types.
+		target.SetString(sv.Convert(scanTypeString).String())
+	case sv.Type().ConvertibleTo(target.Type()) && sv.Kind() != reflect.String && target.Kind() != reflect.String:
+		converted := sv.Convert(target.Type())
+		if !reflect.DeepEqual(converted.Convert(sv.Type()).Interface(), value) {
+			return &DriverError{Message: fmt.Sprintf("Value %v overflows %s.", value, target.Type())}
+		}
+		target.Set(converted)
+	default:
+		return &DriverError{Message: fmt.Sprintf("Can't assign a value of type %T to %s.", value, target.Type()), Err: ErrUnsupportedType}
+	}
+
+	return nil
+}
+
+// ScanStruct returns a scan destination copying a STRUCT column value into the
+// struct pointed to by dest, see Struct.ScanInto:
+//
+//	var address Address
+//	err := row.Scan(siodb.ScanStruct(&address))
+func ScanStruct(dest interface{}) sql.Scanner {
+	return &structScanner{dest}
+}
+
+type structScanner struct {
+	dest interface{}
+}
+
+func (ss *structScanner) Scan(src interface{}) error {
+
+	switch v := src.(type) {
+	case Struct:
+		return v.ScanInto(ss.dest)
+	case nil:
+		return nil
+	default:
+		return &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T as a structured value.", src), Err: ErrUnsupportedType}
+	}
+}
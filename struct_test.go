@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"testing"
+)
+
+type testGeo struct {
+	Lat float64
+	Lon float64
+}
+
+type testAddress struct {
+	Street string  `siodb:"STREET"`
+	Number int     `siodb:"NO"`
+	Floor  *int32  `siodb:"FLOOR"`
+	Geo    testGeo `siodb:"GEO"`
+	Secret string  `siodb:"-"`
+}
+
+func TestReadStructColumn(t *testing.T) {
+
+	columns := []*ColumnDescription{{
+		Name: "ADDRESS",
+		Type: ColumnDataType_COLUMN_DATA_TYPE_STRUCT,
+		Attribute: []*AttributeDescription{
+			{Name: "STREET", Type: ColumnDataType_COLUMN_DATA_TYPE_TEXT},
+			{Name: "NO", Type: ColumnDataType_COLUMN_DATA_TYPE_INT32},
+			{Name: "FLOOR", Type: ColumnDataType_COLUMN_DATA_TYPE_INT32, IsNull: true},
+			{Name: "GEO", Type: ColumnDataType_COLUMN_DATA_TYPE_STRUCT, Attribute: []*AttributeDescription{
+				{Name: "LAT", Type: ColumnDataType_COLUMN_DATA_TYPE_DOUBLE},
+				{Name: "LON", Type: ColumnDataType_COLUMN_DATA_TYPE_DOUBLE},
+			}},
+		},
+	}}
+
+	row := []byte{0x04} // Null bitmask: FLOOR is null
+	row = append(row, 4, 'M', 'a', 'i', 'n', 12)
+	row = append(row, 0, 0, 0, 0, 0, 0, 0xf8, 0x3f) // 1.5
+	row = append(row, 0, 0, 0, 0, 0, 0, 0x04, 0xc0) // -2.5
+	stream := append([]byte{byte(len(row))}, row...)
+
+	sc := newRowsConn(stream)
+	dest := make([]driver.Value, 1)
+	if err := sc.readRow(dest, columns); err != nil {
+		t.Fatalf("readRow: %v", err)
+	}
+
+	value, ok := dest[0].(Struct)
+	if !ok {
+		t.Fatalf("readRow: Struct expected, got %T", dest[0])
+	}
+	m := value.Map()
+	if m["STREET"] != "Main" || m["NO"] != int32(12) || m["FLOOR"] != nil || m["GEO"].(map[string]interface{})["LON"] != -2.5 {
+		t.Fatalf("Map: unexpected %v", m)
+	}
+
+	var address testAddress
+	if err := ScanStruct(&address).Scan(value); err != nil {
+		t.Fatalf("ScanStruct: %v", err)
+	}
+	if address.Street != "Main" || address.Number != 12 || address.Floor != nil || address.Geo != (testGeo{1.5, -2.5}) {
+		t.Fatalf("ScanStruct: unexpected %+v", address)
+	}
+}
+
+type testDocument struct {
+	ID      string
+	Body    string
+	Meta    string
+	Kind    testKind
+	Counter int8
+}
+
+type testKind string
+
+func TestStructScanIntoStrings(t *testing.T) {
+
+	value := Struct{Fields: []StructField{
+		{Name: "ID", Value: UUID{0x12, 0x3e, 0x45, 0x67}.String()},
+		{Name: "BODY", Value: XML("<a/>")},
+		{Name: "META", Value: []byte(`{"a":1}`)},
+		{Name: "KIND", Value: "note"},
+		{Name: "COUNTER", Value: int32(7)},
+	}}
+
+	var doc testDocument
+	if err := value.ScanInto(&doc); err != nil {
+		t.Fatalf("ScanInto: %v", err)
+	}
+	expected := testDocument{"123e4567-0000-0000-0000-000000000000", "<a/>", `{"a":1}`, "note", 7}
+	if doc != expected {
+		t.Fatalf("ScanInto: %+v != %+v", doc, expected)
+	}
+
+	// Numbers are not converted to strings.
+	var numbers struct{ Counter string }
+	if err := value.ScanInto(&numbers); err == nil {
+		t.Fatalf("ScanInto: no error for a number into a string")
+	}
+}
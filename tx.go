@@ -0,0 +1,112 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+)
+
+type siodbTx struct {
+	sc       *siodbConn
+	readOnly bool
+}
+
+func (tx *siodbTx) Commit() error {
+	return tx.end("COMMIT TRANSACTION")
+}
+
+func (tx *siodbTx) Rollback() error {
+	return tx.end("ROLLBACK TRANSACTION")
+}
+
+func (tx *siodbTx) end(query string) error {
+
+	sc := tx.sc
+	if sc == nil || sc.tx != tx {
+		return &DriverError{Message: "Transaction has already been committed or rolled back."}
+	}
+	sc.tx = nil
+	tx.sc = nil
+
+	return sc.execTxCommand(query)
+}
+
+// execTxCommand sends a transaction control statement and checks its response.
+// Once the statement reached the server, failing to read the answer leaves the
+// transaction state unknown: the connection is then marked as bad.
+func (sc *siodbConn) execTxCommand(query string) error {
+
+	var sr ServerResponse
+	var err error
+
+	if err = sc.writeServerCommand(query); err != nil {
+		return err
+	}
+
+	if sr, err = sc.readServer(); err != nil {
+		return &DriverError{Message: "Fail to read server response to '" + query + "': " + err.Error()}
+	}
+
+	return checkServerError(sr.Message)
+}
+
+func checkTxOptions(opts driver.TxOptions) error {
+
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault:
+		return nil
+	default:
+		return &DriverError{Message: "Isolation level '" + sql.IsolationLevel(opts.Isolation).String() + "' not supported."}
+	}
+}
+
+// isReadOnlyStatement reports whether every statement of the query only reads
+// data and thus can be run inside a read-only transaction.
+func isReadOnlyStatement(query string) bool {
+
+	for _, statement := range splitStatements(query) {
+		switch strings.ToUpper(firstKeyword(statement)) {
+		case "", "SELECT", "SHOW", "DESCRIBE":
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// firstKeyword returns the first word of the statement after the leading
+// parentheses, blanks and comments, or its first character if it doesn't
+// start with a word. It returns "" for an empty statement.
+func firstKeyword(statement string) string {
+
+	i := 0
+	for i < len(statement) {
+		c := statement[i]
+		if c == '(' || c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			i++
+			continue
+		}
+		if c == '-' || c == '/' {
+			if end := skipQuoted(statement, i); end >= 0 {
+				i = end + 1
+				continue
+			}
+		}
+		break
+	}
+
+	end := i
+	for end < len(statement) && isIdentStart(statement[end]) {
+		end++
+	}
+	if end == i && end < len(statement) {
+		end++
+	}
+
+	return statement[i:end]
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestIsReadOnlyStatement(t *testing.T) {
+
+	tests := []struct {
+		query    string
+		readOnly bool
+	}{
+		{"", true},
+		{"  ;  ", true},
+		{"SELECT * FROM t", true},
+		{"select 1", true},
+		{"(SELECT a FROM t) UNION (SELECT b FROM u)", true},
+		{"SHOW DATABASES", true},
+		{"DESCRIBE TABLE t", true},
+		{"-- comment\nSELECT 1", true},
+		{"/* comment */ SELECT 1", true},
+		{"SELECT 1; -- trailing comment", true},
+		{"SELECT 1; SELECT 2;", true},
+		{"SELECT ';DELETE FROM t' FROM t", true},
+		{"SELECT 1 AS \"a;DELETE\"", true},
+		{"SELECT 1 /* ; DELETE FROM t */", true},
+		{"DELETE FROM t", false},
+		{"INSERT INTO t VALUES (1)", false},
+		{"SELECT 1; DELETE FROM t", false},
+		{"SELECT 1;DELETE FROM t", false},
+		{"/* SELECT */ UPDATE t SET a = 1", false},
+		{"SELECT 1; 'x'", false},
+	}
+
+	for _, test := range tests {
+		if readOnly := isReadOnlyStatement(test.query); readOnly != test.readOnly {
+			t.Fatalf("isReadOnlyStatement(%q): %t != %t", test.query, readOnly, test.readOnly)
+		}
+	}
+}
+
+func TestCheckTxOptions(t *testing.T) {
+
+	if err := checkTxOptions(driver.TxOptions{}); err != nil {
+		t.Fatalf("checkTxOptions: %v", err)
+	}
+	if err := checkTxOptions(driver.TxOptions{ReadOnly: true}); err != nil {
+		t.Fatalf("checkTxOptions: %v", err)
+	}
+	if err := checkTxOptions(driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelSerializable)}); err == nil {
+		t.Fatalf("checkTxOptions: no error for an unsupported isolation level")
+	}
+}
+
+func TestBeginTxReadOnly(t *testing.T) {
+
+	var stream []byte
+	stream = appendResponse(t, stream, &ServerResponse{RequestID: 1}) // BEGIN TRANSACTION
+	stream = appendResponse(t, stream, &ServerResponse{RequestID: 1}) // COMMIT TRANSACTION
+
+	sc := newFakeServerConn(t, stream)
+	tx, err := sc.BeginTx(context.Background(), driver.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+
+	if _, err = sc.BeginTx(context.Background(), driver.TxOptions{}); err == nil {
+		t.Fatalf("BeginTx: no error for a nested transaction")
+	}
+	if _, err = sc.ExecContext(context.Background(), "SELECT 1; DELETE FROM t", nil); err == nil {
+		t.Fatalf("ExecContext: write statement allowed in a read-only transaction")
+	}
+	if _, err = sc.QueryContext(context.Background(), "UPDATE t SET a = ?", []driver.NamedValue{{Ordinal: 1, Value: int64(1)}}); err == nil {
+		t.Fatalf("QueryContext: write statement allowed in a read-only transaction")
+	}
+	if _, err = sc.ExecBatch(context.Background(), "SELECT 1; DROP TABLE t", BatchOptions{}); err == nil {
+		t.Fatalf("ExecBatch: write statement allowed in a read-only transaction")
+	}
+
+	if err = tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err = tx.Rollback(); err == nil {
+		t.Fatalf("Rollback: no error after Commit")
+	}
+	if sc.tx != nil || !sc.IsValid() {
+		t.Fatalf("Commit: transaction still in progress")
+	}
+}
+
+func TestBeginTxServerError(t *testing.T) {
+
+	stream := appendResponse(t, nil, &ServerResponse{
+		RequestID: 1,
+		Message:   []*StatusMessage{{StatusCode: 2, Text: "Transaction already started"}},
+	})
+
+	sc := newFakeServerConn(t, stream)
+	_, err := sc.BeginTx(context.Background(), driver.TxOptions{})
+	var serverErr *ServerError
+	if !errors.As(err, &serverErr) || serverErr.Code != 2 {
+		t.Fatalf("BeginTx: ServerError expected, got %v", err)
+	}
+	if sc.tx != nil || !sc.IsValid() {
+		t.Fatalf("BeginTx: transaction registered after a server error")
+	}
+}
+
+func TestBeginTxIsolationLevel(t *testing.T) {
+
+	sc := newFakeServerConn(t, nil)
+	if _, err := sc.BeginTx(context.Background(), driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelReadCommitted)}); err == nil {
+		t.Fatalf("BeginTx: no error for an unsupported isolation level")
+	}
+	if sc.tx != nil {
+		t.Fatalf("BeginTx: transaction registered after an error")
+	}
+}
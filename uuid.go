@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+)
+
+// UUID is a 16 bytes universally unique identifier.
+//
+// UUID columns are returned in their canonical text form so that they can be
+// scanned into string and []byte as well as into UUID.
+type UUID [16]byte
+
+// ParseUUID parses a UUID in its canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx,
+// hyphens being optional.
+func ParseUUID(s string) (UUID, error) {
+
+	var u UUID
+
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return u, &DriverError{Message: "Invalid UUID '" + s + "'."}
+		}
+		s = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return u, &DriverError{Message: "Invalid UUID '" + s + "'."}
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return u, &DriverError{Message: "Invalid UUID '" + s + "'.", Err: err}
+	}
+
+	return u, nil
+}
+
+// String returns the canonical form of the UUID.
+func (u UUID) String() string {
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (u *UUID) UnmarshalText(text []byte) error {
+
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scan implements the sql.Scanner interface. It accepts the text form of a
+// UUID as string or []byte, as well as its 16 raw bytes.
+func (u *UUID) Scan(src interface{}) error {
+
+	switch v := src.(type) {
+	case UUID:
+		*u = v
+		return nil
+	case string:
+		return u.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == len(u) {
+			copy(u[:], v)
+			return nil
+		}
+		return u.UnmarshalText(v)
+	default:
+		return &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T into a UUID.", src), Err: ErrUnsupportedType}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+var testUUID = UUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+const testUUIDText = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestReadUUIDField(t *testing.T) {
+
+	sc := newStreamConn(testUUID[:])
+
+	value, err := sc.readFieldData(ColumnDataType_COLUMN_DATA_TYPE_UUID)
+	if err != nil || value != testUUIDText {
+		t.Fatalf("readFieldData: %v, %v", value, err)
+	}
+
+	var u UUID
+	if err = u.Scan(value); err != nil || u != testUUID {
+		t.Fatalf("Scan: %v, %v", u, err)
+	}
+}
+
+func TestUUID(t *testing.T) {
+
+	if testUUID.String() != testUUIDText {
+		t.Fatalf("String: %s", testUUID.String())
+	}
+
+	for _, src := range []interface{}{testUUIDText, []byte(testUUIDText), testUUID[:], "123e4567e89b12d3a456426614174000", testUUID} {
+		var u UUID
+		if err := u.Scan(src); err != nil || u != testUUID {
+			t.Fatalf("Scan(%v): %v, %v", src, u, err)
+		}
+	}
+
+	for _, src := range []interface{}{"123e4567", "123e4567+e89b-12d3-a456-426614174000", int64(1)} {
+		var u UUID
+		if err := u.Scan(src); err == nil {
+			t.Fatalf("Scan(%v): error expected", src)
+		}
+	}
+
+	text, err := bindText("SELECT * FROM t WHERE id = ?", []driver.NamedValue{{Ordinal: 1, Value: testUUID}}, time.UTC)
+	if err != nil || text != "SELECT * FROM t WHERE id = '"+testUUIDText+"'" {
+		t.Fatalf("bind: %q, %v", text, err)
+	}
+}
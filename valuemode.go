@@ -0,0 +1,231 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueMode controls the Go types of the values returned for the columns.
+// Modes can be combined. The zero value returns the native types listed in
+// the README.
+type ValueMode uint
+
+const (
+	// ValueModeStrict returns only driver.Value types: integers are widened
+	// to int64, floats to float64, BIGUINT values above math.MaxInt64 are
+	// returned as decimal strings, XML and intervals as strings, JSON and
+	// structured values as JSON []byte.
+	ValueModeStrict ValueMode = 1 << iota
+	// ValueModeUint64AsString returns BIGUINT values as decimal strings.
+	ValueModeUint64AsString
+	// ValueModeTimestampAsString returns dates and times as strings in the
+	// format used for bound parameters.
+	ValueModeTimestampAsString
+	// ValueModeBinaryAsHex returns BLOB values as lowercase hexadecimal strings.
+	ValueModeBinaryAsHex
+)
+
+var valueModeNames = []struct {
+	mode ValueMode
+	name string
+}{
+	{ValueModeStrict, "strict"},
+	{ValueModeUint64AsString, "uint64_as_string"},
+	{ValueModeTimestampAsString, "timestamp_as_string"},
+	{ValueModeBinaryAsHex, "binary_as_hex"},
+}
+
+// ParseValueMode parses a comma separated list of mode names, for example
+// "strict,binary_as_hex". "native" and the empty string are the zero mode.
+func ParseValueMode(s string) (ValueMode, error) {
+
+	var mode ValueMode
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "native" {
+			continue
+		}
+		found := false
+		for _, m := range valueModeNames {
+			if m.name == name {
+				mode |= m.mode
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, &DriverError{Message: "Unknown value mode '" + name + "'."}
+		}
+	}
+
+	return mode, nil
+}
+
+// String returns the comma separated names of the modes.
+func (m ValueMode) String() string {
+
+	var names []string
+	for _, mode := range valueModeNames {
+		if m&mode.mode != 0 {
+			names = append(names, mode.name)
+		}
+	}
+	if len(names) == 0 {
+		return "native"
+	}
+
+	return strings.Join(names, ",")
+}
+
+// mapValue converts a decoded non-null value according to the modes.
+func (m ValueMode) mapValue(columnType ColumnDataType, attributes []*AttributeDescription, value driver.Value) (driver.Value, error) {
+
+	if m == 0 {
+		return value, nil
+	}
+
+	switch v := value.(type) {
+	case int8:
+		if m&ValueModeStrict != 0 {
+			return int64(v), nil
+		}
+	case uint8:
+		if m&ValueModeStrict != 0 {
+			return int64(v), nil
+		}
+	case int16:
+		if m&ValueModeStrict != 0 {
+			return int64(v), nil
+		}
+	case uint16:
+		if m&ValueModeStrict != 0 {
+			return int64(v), nil
+		}
+	case int32:
+		if m&ValueModeStrict != 0 {
+			return int64(v), nil
+		}
+	case uint32:
+		if m&ValueModeStrict != 0 {
+			return int64(v), nil
+		}
+	case uint64:
+		if m&ValueModeUint64AsString != 0 || (m&ValueModeStrict != 0 && v > math.MaxInt64) {
+			return strconv.FormatUint(v, 10), nil
+		}
+		if m&ValueModeStrict != 0 {
+			return int64(v), nil
+		}
+	case float32:
+		if m&ValueModeStrict != 0 {
+			return float64(v), nil
+		}
+	case []byte:
+		if m&ValueModeBinaryAsHex != 0 && columnType == ColumnDataType_COLUMN_DATA_TYPE_BINARY {
+			return hex.EncodeToString(v), nil
+		}
+	case time.Time:
+		if m&ValueModeTimestampAsString != 0 {
+			return formatTime(columnType, v), nil
+		}
+	case Interval:
+		if m&ValueModeStrict != 0 {
+			return v.String(), nil
+		}
+	case XML:
+		if m&ValueModeStrict != 0 {
+			return string(v), nil
+		}
+	case Struct:
+		return m.mapStruct(attributes, v)
+	}
+
+	return value, nil
+}
+
+// mapStruct converts the attributes of a structured value. In strict mode
+// the value is returned as a JSON object.
+func (m ValueMode) mapStruct(attributes []*AttributeDescription, value Struct) (driver.Value, error) {
+
+	var err error
+
+	mapped := Struct{Fields: make([]StructField, len(value.Fields))}
+	for idx, field := range value.Fields {
+		mapped.Fields[idx].Name = field.Name
+		if field.Value != nil && idx < len(attributes) {
+			if mapped.Fields[idx].Value, err = m.mapValue(attributes[idx].Type, attributes[idx].Attribute, field.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if m&ValueModeStrict != 0 {
+		return json.Marshal(mapped.Map())
+	}
+
+	return mapped, nil
+}
+
+// formatTime formats a date or time with the layout of its column data type.
+func formatTime(columnType ColumnDataType, t time.Time) string {
+
+	switch columnType {
+	case ColumnDataType_COLUMN_DATA_TYPE_DATE:
+		return t.Format("2006-01-02")
+	case ColumnDataType_COLUMN_DATA_TYPE_TIME:
+		return t.Format("15:04:05.999999999")
+	case ColumnDataType_COLUMN_DATA_TYPE_TIME_WITH_TZ:
+		return t.Format("15:04:05.999999999 -07:00")
+	case ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP_WITH_TZ:
+		return t.Format("2006-01-02 15:04:05.999999999 -07:00")
+	default:
+		return t.Format("2006-01-02 15:04:05.999999999")
+	}
+}
+
+// scanType returns the Go type of the values returned for a column data type.
+func (m ValueMode) scanType(columnType ColumnDataType) reflect.Type {
+
+	scanType := columnScanType(columnType)
+	if m == 0 {
+		return scanType
+	}
+
+	switch {
+	case columnType == ColumnDataType_COLUMN_DATA_TYPE_UINT64 && m&ValueModeUint64AsString != 0:
+		return scanTypeString
+	case columnType == ColumnDataType_COLUMN_DATA_TYPE_UINT64 && m&ValueModeStrict != 0:
+		// int64, or a string when the value overflows.
+		return scanTypeInterface
+	case columnType == ColumnDataType_COLUMN_DATA_TYPE_BINARY && m&ValueModeBinaryAsHex != 0:
+		return scanTypeString
+	case scanType == scanTypeTime && m&ValueModeTimestampAsString != 0:
+		return scanTypeString
+	case m&ValueModeStrict == 0:
+		return scanType
+	}
+
+	switch scanType {
+	case scanTypeInt8, scanTypeUint8, scanTypeInt16, scanTypeUint16, scanTypeInt32, scanTypeUint32:
+		return scanTypeInt64
+	case scanTypeFloat32:
+		return scanTypeFloat64
+	case scanTypeInterval, scanTypeXML:
+		return scanTypeString
+	case scanTypeStruct:
+		return scanTypeBytes
+	default:
+		return scanType
+	}
+}
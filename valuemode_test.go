@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseValueMode(t *testing.T) {
+
+	mode, err := ParseValueMode("strict, binary_as_hex")
+	if err != nil {
+		t.Fatalf("ParseValueMode: %v", err)
+	}
+	if mode != ValueModeStrict|ValueModeBinaryAsHex {
+		t.Fatalf("ParseValueMode: %v", mode)
+	}
+	if mode.String() != "strict,binary_as_hex" {
+		t.Fatalf("String: %q", mode.String())
+	}
+
+	if _, err = ParseValueMode("strict,bogus"); err == nil {
+		t.Fatalf("ParseValueMode: no error for an unknown mode")
+	}
+}
+
+func TestValueModeMapValue(t *testing.T) {
+
+	ts := time.Date(2020, time.June, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		mode       ValueMode
+		columnType ColumnDataType
+		value      driver.Value
+		expected   driver.Value
+	}{
+		{0, ColumnDataType_COLUMN_DATA_TYPE_INT8, int8(-1), int8(-1)},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_INT8, int8(-1), int64(-1)},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_UINT32, uint32(math.MaxUint32), int64(math.MaxUint32)},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_UINT64, uint64(42), int64(42)},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_UINT64, uint64(math.MaxUint64), "18446744073709551615"},
+		{ValueModeUint64AsString, ColumnDataType_COLUMN_DATA_TYPE_UINT64, uint64(42), "42"},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_FLOAT, float32(0.5), float64(0.5)},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_XML, XML("<a/>"), "<a/>"},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_TIME_INTERVAL, Interval{Duration: time.Second}, "PT1S"},
+		{ValueModeBinaryAsHex, ColumnDataType_COLUMN_DATA_TYPE_BINARY, []byte{0xca, 0xfe}, "cafe"},
+		{ValueModeTimestampAsString, ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP, ts, "2020-06-01 12:30:00"},
+		{ValueModeTimestampAsString, ColumnDataType_COLUMN_DATA_TYPE_DATE, ts, "2020-06-01"},
+		{ValueModeTimestampAsString, ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP_WITH_TZ, ts, "2020-06-01 12:30:00 +00:00"},
+		{ValueModeStrict, ColumnDataType_COLUMN_DATA_TYPE_TIMESTAMP, ts, ts},
+	}
+
+	for _, test := range tests {
+		value, err := test.mode.mapValue(test.columnType, nil, test.value)
+		if err != nil {
+			t.Fatalf("mapValue(%v, %s): %v", test.mode, test.columnType, err)
+		}
+		if !reflect.DeepEqual(value, test.expected) {
+			t.Fatalf("mapValue(%v, %s): %#v != %#v", test.mode, test.columnType, value, test.expected)
+		}
+	}
+}
+
+func TestValueModeStrictStruct(t *testing.T) {
+
+	attributes := []*AttributeDescription{
+		{Name: "id", Type: ColumnDataType_COLUMN_DATA_TYPE_UINT16},
+		{Name: "name", Type: ColumnDataType_COLUMN_DATA_TYPE_TEXT, IsNull: true},
+	}
+	value := Struct{Fields: []StructField{{"id", uint16(7)}, {"name", nil}}}
+
+	mapped, err := ValueModeStrict.mapValue(ColumnDataType_COLUMN_DATA_TYPE_STRUCT, attributes, value)
+	if err != nil {
+		t.Fatalf("mapValue: %v", err)
+	}
+	if string(mapped.([]byte)) != `{"id":7,"name":null}` {
+		t.Fatalf("mapValue: %s", mapped)
+	}
+
+	if scanType := ValueModeStrict.scanType(ColumnDataType_COLUMN_DATA_TYPE_STRUCT); scanType != scanTypeBytes {
+		t.Fatalf("scanType: %v", scanType)
+	}
+	if scanType := ValueModeStrict.scanType(ColumnDataType_COLUMN_DATA_TYPE_INT16); scanType != scanTypeInt64 {
+		t.Fatalf("scanType: %v", scanType)
+	}
+}
+
+func TestValueModeMapErrorKeepsSync(t *testing.T) {
+
+	columns := []*ColumnDescription{
+		{Name: "S", Type: ColumnDataType_COLUMN_DATA_TYPE_STRUCT, Attribute: []*AttributeDescription{
+			{Name: "X", Type: ColumnDataType_COLUMN_DATA_TYPE_DOUBLE},
+		}},
+		{Name: "N", Type: ColumnDataType_COLUMN_DATA_TYPE_INT64},
+	}
+
+	// NaN can't be marshaled to JSON.
+	nan := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nan, math.Float64bits(math.NaN()))
+	var stream []byte
+	stream = append(stream, 9)
+	stream = append(stream, nan...)
+	stream = append(stream, 1)
+	stream = append(stream, 9, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0)
+
+	sc := newRowsConn(stream)
+	sc.cfg.ValueMode = ValueModeStrict
+	dest := make([]driver.Value, 2)
+
+	if err := sc.readRow(dest, columns); err == nil {
+		t.Fatalf("readRow: mapping error expected")
+	}
+	if !sc.IsValid() {
+		t.Fatalf("readRow: connection marked bad after a mapping error")
+	}
+	if err := sc.readRow(dest, columns); err != nil || dest[1] != int64(2) {
+		t.Fatalf("readRow: next row not in sync: %v, %v", dest, err)
+	}
+	if err := sc.readRow(dest, columns); err != io.EOF {
+		t.Fatalf("readRow: io.EOF expected, got %v", err)
+	}
+}
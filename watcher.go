@@ -0,0 +1,100 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"time"
+)
+
+// cancelWatcher ties the I/O of one request/response cycle to a context:
+// the context deadline is set on the connection and a cancellation
+// interrupts the pending read or write by moving the deadline to the past.
+type cancelWatcher struct {
+	sc     *siodbConn
+	ctx    context.Context
+	done   chan struct{}
+	exited chan struct{}
+}
+
+// startWatcher returns the context error if it is already done, without any
+// I/O performed. It returns a nil watcher for contexts that can't be cancelled.
+func (sc *siodbConn) startWatcher(ctx context.Context) (*cancelWatcher, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil {
+		return nil, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		sc.netConn.SetDeadline(deadline)
+	}
+
+	w := &cancelWatcher{
+		sc:     sc,
+		ctx:    ctx,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(w.exited)
+		select {
+		case <-ctx.Done():
+			sc.debug("cancelWatcher | Context done: %v.", ctx.Err())
+			sc.netConn.SetDeadline(time.Unix(1, 0))
+		case <-w.done:
+		}
+	}()
+
+	return w, nil
+}
+
+// check returns err unchanged unless the context is done. In that case the
+// stream is desynchronized: the connection is closed and the context error is
+// returned wrapped with driver.ErrBadConn.
+func (w *cancelWatcher) check(err error) error {
+
+	if w == nil || err == nil {
+		return err
+	}
+
+	// The connection deadline may expire just before the context does.
+	ctxErr := w.ctx.Err()
+	if deadline, ok := w.ctx.Deadline(); ok && ctxErr == nil && !time.Now().Before(deadline) {
+		ctxErr = context.DeadlineExceeded
+	}
+	if ctxErr == nil {
+		return err
+	}
+
+	w.sc.debug("cancelWatcher | Closing connection after: %v.", err)
+	w.sc.bad = true
+	w.sc.netConn.Close()
+
+	return &badConnError{ctxErr}
+}
+
+// stop ends the watch and clears the connection deadline.
+func (w *cancelWatcher) stop() {
+
+	if w == nil {
+		return
+	}
+
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+	close(w.done)
+	<-w.exited
+
+	if !w.sc.bad {
+		w.sc.netConn.SetDeadline(time.Time{})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+// newPipeConn returns a connection whose server side swallows every command
+// and never answers.
+func newPipeConn() *siodbConn {
+
+	client, server := net.Pipe()
+	go io.Copy(ioutil.Discard, server)
+
+	return newConn(client, Config{})
+}
+
+func TestExecContextDeadline(t *testing.T) {
+
+	sc := newPipeConn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := sc.ExecContext(ctx, "DELETE FROM test.t", nil)
+	if !errors.Is(err, driver.ErrBadConn) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ExecContext: unexpected error %v", err)
+	}
+	if !sc.bad {
+		t.Fatalf("ExecContext: connection not marked as bad")
+	}
+}
+
+func TestQueryContextCancel(t *testing.T) {
+
+	sc := newPipeConn()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := sc.QueryContext(ctx, "SELECT * FROM test.t", nil)
+	if !errors.Is(err, driver.ErrBadConn) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("QueryContext: unexpected error %v", err)
+	}
+}
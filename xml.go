@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"database/sql/driver"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// XML is an XML document. XML column values can be scanned into XML, string or []byte.
+type XML string
+
+// Decoder returns an encoding/xml decoder streaming the document.
+func (x XML) Decoder() *xml.Decoder {
+	return xml.NewDecoder(strings.NewReader(string(x)))
+}
+
+// Unmarshal parses the document into v, see xml.Unmarshal.
+func (x XML) Unmarshal(v interface{}) error {
+	return x.Decoder().Decode(v)
+}
+
+// Value implements the driver.Valuer interface.
+func (x XML) Value() (driver.Value, error) {
+	return string(x), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (x *XML) Scan(src interface{}) error {
+
+	switch v := src.(type) {
+	case XML:
+		*x = v
+	case string:
+		*x = XML(v)
+	case []byte:
+		*x = XML(v)
+	case nil:
+		*x = ""
+	default:
+		return &DriverError{Message: fmt.Sprintf("Can't scan a value of type %T into XML.", src), Err: ErrUnsupportedType}
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2019-2020 Siodb GmbH. All rights reserved.
+// Use of this source code is governed by a license that can be found
+// in the LICENSE file.
+
+package siodb
+
+import (
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+type xmlFeed struct {
+	Partner string `xml:"partner,attr"`
+	Items   []int  `xml:"item"`
+}
+
+func TestReadNTextAndXMLFields(t *testing.T) {
+
+	// TODO: the NTEXT bytes follow the assumed UTF-16LE layout, replace them
+	// with values captured from the server once available.
+	text := "汉字 𝄞"
+	units := utf16.Encode([]rune(text))
+	stream := []byte{byte(len(units) * 2)}
+	for _, unit := range units {
+		stream = binary.LittleEndian.AppendUint16(stream, unit)
+	}
+	doc := `<feed partner="p"><item>1</item><item>2</item></feed>`
+	stream = append(stream, byte(len(doc)))
+	stream = append(stream, doc...)
+
+	sc := newStreamConn(stream)
+
+	value, err := sc.readFieldData(ColumnDataType_COLUMN_DATA_TYPE_NTEXT)
+	if err != nil || value != text {
+		t.Fatalf("readFieldData(NTEXT): %q, %v", value, err)
+	}
+
+	value, err = sc.readFieldData(ColumnDataType_COLUMN_DATA_TYPE_XML)
+	if err != nil || value != XML(doc) {
+		t.Fatalf("readFieldData(XML): %v, %v", value, err)
+	}
+
+	var feed xmlFeed
+	if err = value.(XML).Unmarshal(&feed); err != nil || feed.Partner != "p" || len(feed.Items) != 2 {
+		t.Fatalf("Unmarshal: %+v, %v", feed, err)
+	}
+}